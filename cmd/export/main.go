@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/EMus88/Market/configs"
+	"github.com/EMus88/Market/internal/repository"
+	"github.com/EMus88/Market/internal/service"
+	"github.com/EMus88/Market/internal/spreadsheet"
+
+	"github.com/sirupsen/logrus"
+)
+
+//export of all products to csv, xlsx or json lines file, it is run from root of project:
+//	go run ./cmd/export -out catalog.xlsx
+//	go run ./cmd/export -format jsonl > catalog.jsonl
+func main() {
+	out := flag.String("out", "", "file to write, stdout by default")
+	format := flag.String("format", "", "csv, xlsx or jsonl, by extension of out file or csv by default")
+	flag.Parse()
+
+	//init logger
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+
+	if *format == "" {
+		*format = spreadsheet.FormatCSV
+		if ext := spreadsheet.FormatOf(*out); ext != "" {
+			*format = ext
+		}
+	}
+	*format = strings.ToLower(*format)
+	if err := service.ExportFormat(*format); err != nil {
+		logger.Fatal(err)
+	}
+
+	//init configs
+	if err := configs.InitConfig(); err != nil {
+		logger.Fatal(err)
+	}
+	//db connection
+	db, err := repository.NewDB(context.Background())
+	if err != nil {
+		logger.Fatal("No database connection ")
+	}
+	defer db.Close(context.Background())
+	s := service.NewService(repository.NewRepository(db, logger), logger)
+
+	w := os.Stdout
+	if *out != "" {
+		if w, err = os.Create(*out); err != nil {
+			logger.Fatal(err)
+		}
+	}
+	buf := bufio.NewWriter(w)
+	if err := s.ExportProducts(context.Background(), buf, *format); err != nil {
+		logger.Fatal(err)
+	}
+	if err := buf.Flush(); err != nil {
+		logger.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		logger.Fatal(err)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/EMus88/Market/configs"
+	"github.com/EMus88/Market/internal/repository"
+	"github.com/EMus88/Market/internal/service"
+	"github.com/EMus88/Market/internal/spreadsheet"
+
+	"github.com/sirupsen/logrus"
+)
+
+//import of products from csv or xlsx file, it is run from root of project:
+//	go run ./cmd/import -file prices.xlsx -dry-run -map "Наименование=name,Цена=price"
+func main() {
+	file := flag.String("file", "", "csv or xlsx file with products")
+	dryRun := flag.Bool("dry-run", false, "only check file, nothing is written")
+	mapping := flag.String("map", "", `fields of columns with other titles, like "Наименование=name,Цена=price"`)
+	flag.Parse()
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	//init logger
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+
+	//init configs
+	if err := configs.InitConfig(); err != nil {
+		logger.Fatal(err)
+	}
+	//db connection
+	db, err := repository.NewDB(context.Background())
+	if err != nil {
+		logger.Fatal("No database connection ")
+	}
+	defer db.Close(context.Background())
+	s := service.NewService(repository.NewRepository(db, logger), logger)
+
+	f, err := os.Open(*file)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := spreadsheet.Read(f, spreadsheet.FormatOf(*file))
+	if err != nil {
+		logger.Fatal(err)
+	}
+	columns, err := parseMapping(*mapping)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	report, err := s.ImportProducts(rows, columns, *dryRun, nil)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	//report is written to stdout, file with errors is exit code 1
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+//pairs "title=field" separated by commas
+func parseMapping(s string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("error: not valid mapping %q, must be title=field", pair)
+		}
+		mapping[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return mapping, nil
+}
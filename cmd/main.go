@@ -10,9 +10,14 @@ import (
 	"time"
 
 	"github.com/EMus88/Market/configs"
+	"github.com/EMus88/Market/internal/feed"
 	"github.com/EMus88/Market/internal/handler"
+	"github.com/EMus88/Market/internal/imaging"
+	"github.com/EMus88/Market/internal/payment"
 	"github.com/EMus88/Market/internal/repository"
 	"github.com/EMus88/Market/internal/service"
+	"github.com/EMus88/Market/internal/shipping"
+	"github.com/EMus88/Market/internal/storage"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -58,7 +63,32 @@ func main() {
 	//init main components
 	r := repository.NewRepository(db, logger)
 	s := service.NewService(r, logger)
+	gateway, err := payment.New(viper.GetString("payment.provider"), os.Getenv("PAYMENT_SECRET"))
+	if err != nil {
+		logger.Fatal(err)
+	}
+	s.UsePayment(gateway)
+	if s.Shipping, err = shipping.Load(); err != nil {
+		logger.Fatal(err)
+	}
+	if s.Storage, err = storage.Load(); err != nil {
+		logger.Fatal(err)
+	}
+	s.ImageSize = viper.GetInt64("images.max_size")
+	if s.Images, err = imaging.Load(); err != nil {
+		logger.Fatal(err)
+	}
+	//feeds for marketplaces are optional
+	if viper.IsSet("feed") {
+		if s.Feeds, err = feed.Load(); err != nil {
+			logger.Fatal(err)
+		}
+	}
 	h := handler.NewHandler(s, logger)
+	h.Storefront = handler.Storefront{
+		Enabled: viper.GetBool("storefront.enabled"),
+		MaxAge:  viper.GetDuration("storefront.max_age"),
+	}
 
 	//init server
 	adr := fmt.Sprint(viper.GetString("host"), ":", viper.GetString("port"))
@@ -68,6 +98,11 @@ func main() {
 	}
 	//run server
 	go server.ListenAndServe()
+	//apply scheduled prices in background
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.RunPriceScheduler(ctx, viper.GetDuration("prices.schedule_interval"))
+	//generate sizes of uploaded images in background
+	go s.RunImagePipeline(ctx, viper.GetDuration("images.interval"))
 
 	logger.Infof("Server started by address: %s", adr)
 
@@ -75,6 +110,7 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 	<-quit
+	cancel()
 	<-time.After(time.Second * 2)
 	logrus.Println("Server stopped")
 }
@@ -0,0 +1,197 @@
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/spf13/viper"
+)
+
+//formats of feeds
+const (
+	FormatYML    = "yml"
+	FormatGoogle = "google"
+)
+
+//feed is generated again when it is older
+const defaultTTL = time.Hour
+
+//marketplaces take only first pictures of offer
+const maxPictures = 10
+
+var (
+	ErrFormat = errors.New("error: unknown feed format")
+	ErrConfig = errors.New("error: not valid feed config")
+)
+
+//shop metadata and links of products from config
+type Config struct {
+	TTL time.Duration `mapstructure:"ttl"`
+	//link of product page, {id} is replaced by id of product
+	ProductURL string `mapstructure:"product_url"`
+	Shop       Shop   `mapstructure:"shop"`
+}
+
+type Shop struct {
+	Name    string `mapstructure:"name"`
+	Company string `mapstructure:"company"`
+	URL     string `mapstructure:"url"`
+}
+
+//makes feeds of offers for marketplaces
+type Generator struct {
+	TTL        time.Duration
+	shop       Shop
+	base       *url.URL
+	productURL string
+}
+
+//generator from "feed" section of config
+func Load() (*Generator, error) {
+	var cfg Config
+	if err := viper.UnmarshalKey("feed", &cfg); err != nil {
+		return nil, err
+	}
+	return New(cfg)
+}
+
+func New(cfg Config) (*Generator, error) {
+	base, err := url.Parse(cfg.Shop.URL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("%w: shop url must be absolute", ErrConfig)
+	}
+	if cfg.Shop.Name == "" {
+		return nil, fmt.Errorf("%w: shop name is required", ErrConfig)
+	}
+	if !strings.Contains(cfg.ProductURL, "{id}") {
+		return nil, fmt.Errorf("%w: product url must have {id}", ErrConfig)
+	}
+	if cfg.Shop.Company == "" {
+		cfg.Shop.Company = cfg.Shop.Name
+	}
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &Generator{
+		TTL:        ttl,
+		shop:       cfg.Shop,
+		base:       base,
+		productURL: cfg.ProductURL,
+	}, nil
+}
+
+//writer of offers, offers are encoded one by one
+type Writer interface {
+	Write(product *models.ProductDTO) error
+	//write end of feed, writer can't be used after it
+	Close() error
+}
+
+//writer of feed which starts with shop and categories
+func (g *Generator) NewWriter(w io.Writer, format string, categories []models.Category, at time.Time) (Writer, error) {
+	tree := newTree(categories)
+	switch format {
+	case FormatYML:
+		return g.newYML(w, tree, at)
+	case FormatGoogle:
+		return g.newGoogle(w, tree)
+	}
+	return nil, ErrFormat
+}
+
+func (g *Generator) link(product *models.ProductDTO) string {
+	return strings.Replace(g.productURL, "{id}", url.PathEscape(product.ID), 1)
+}
+
+//absolute links of images, primary image is first, then other images and photos
+func (g *Generator) pictures(product *models.ProductDTO) []string {
+	var links []string
+	for _, img := range product.Images {
+		if img.Primary {
+			links = append(links, g.absolute(img.URL))
+		}
+	}
+	for _, img := range product.Images {
+		if !img.Primary {
+			links = append(links, g.absolute(img.URL))
+		}
+	}
+	for _, photo := range product.Photo {
+		links = append(links, g.absolute(photo))
+	}
+	if len(links) > maxPictures {
+		links = links[:maxPictures]
+	}
+	return links
+}
+
+//images of local storage have links from root of site
+func (g *Generator) absolute(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	return g.base.ResolveReference(u).String()
+}
+
+//categories by names of products
+type tree struct {
+	categories []models.Category
+	byName     map[string]*models.Category
+	byID       map[uuid.UUID]*models.Category
+}
+
+func newTree(categories []models.Category) *tree {
+	t := &tree{
+		categories: categories,
+		byName:     make(map[string]*models.Category, len(categories)),
+		byID:       make(map[uuid.UUID]*models.Category, len(categories)),
+	}
+	for i := range categories {
+		t.byName[categories[i].Name] = &categories[i]
+		t.byID[categories[i].ID] = &categories[i]
+	}
+	return t
+}
+
+//names from root category to category, like "Food > Milk"
+func (t *tree) path(name string) string {
+	category, ok := t.byName[name]
+	if !ok {
+		return name
+	}
+	var names []string
+	seen := make(map[uuid.UUID]bool)
+	for category != nil && !seen[category.ID] {
+		seen[category.ID] = true
+		names = append([]string{category.Name}, names...)
+		if category.ParentID == nil {
+			break
+		}
+		category = t.byID[*category.ParentID]
+	}
+	return strings.Join(names, " > ")
+}
+
+//attributes in order of names
+func params(attributes map[string]interface{}) []param {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]param, 0, len(names))
+	for _, name := range names {
+		list = append(list, param{Name: name, Value: fmt.Sprint(attributes[name])})
+	}
+	return list
+}
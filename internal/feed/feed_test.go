@@ -0,0 +1,91 @@
+package feed
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/go-playground/assert"
+	"github.com/gofrs/uuid"
+)
+
+func Test_New(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		ok   bool
+	}{
+		{name: "Ok", cfg: Config{ProductURL: "https://shop.ru/product/{id}", Shop: Shop{Name: "Market", URL: "https://shop.ru"}}, ok: true},
+		{name: "Relative url", cfg: Config{ProductURL: "https://shop.ru/product/{id}", Shop: Shop{Name: "Market", URL: "/"}}},
+		{name: "Without name", cfg: Config{ProductURL: "https://shop.ru/product/{id}", Shop: Shop{URL: "https://shop.ru"}}},
+		{name: "Without id", cfg: Config{ProductURL: "https://shop.ru/product", Shop: Shop{Name: "Market", URL: "https://shop.ru"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := New(tt.cfg)
+			assert.Equal(t, err == nil, tt.ok)
+			if tt.ok {
+				assert.Equal(t, g.TTL, defaultTTL)
+			}
+		})
+	}
+}
+
+func Test_Writer(t *testing.T) {
+	g, err := New(Config{ProductURL: "https://shop.ru/product/{id}", Shop: Shop{Name: "Market", Company: "ООО \"Маркет\"", URL: "https://shop.ru"}})
+	assert.Equal(t, err, nil)
+	food := uuid.FromStringOrNil("5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10")
+	milk := uuid.FromStringOrNil("6c1f5e3f-9e2d-4b66-8e1b-1c8b3d4d0a21")
+	categories := []models.Category{{ID: food, Name: "food", Visible: true}, {ID: milk, Name: "milk & cream", Visible: true, ParentID: &food}}
+	discount := models.NewMoney(8090, models.BaseCurrency)
+	products := []models.ProductDTO{
+		{
+			ID: "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", Name: "Milk <3.2%>", Weight: 1.05, Category: "milk & cream",
+			Price: models.NewMoney(8990, models.BaseCurrency), Discount: &discount, InStock: true, Attributes: map[string]interface{}{"fat": 3.2, "brand": "Farm"},
+			Photo:  []string{"https://cdn.ru/milk.jpg"},
+			Images: []models.ImageDTO{{URL: "/uploads/products/1.jpg"}, {URL: "/uploads/products/2.jpg", Primary: true}},
+		},
+		{ID: "1d7a2c9f-73b5-4d9a-9d66-4a2f3b0c8e32", Name: "Bread", Weight: 0.5, Description: "Rye", Category: "hidden", Price: models.NewMoney(4990, models.BaseCurrency)},
+	}
+	tests := []struct {
+		format string
+		feed   string
+	}{
+		{
+			format: FormatYML,
+			feed: xmlHeader + `<yml_catalog date="2026-10-18T12:00:00Z"><shop><name>Market</name><company>ООО &#34;Маркет&#34;</company><url>https://shop.ru</url>` +
+				`<currencies><currency id="RUB" rate="1"></currency></currencies>` +
+				`<categories><category id="5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10">food</category><category id="6c1f5e3f-9e2d-4b66-8e1b-1c8b3d4d0a21" parentId="5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10">milk &amp; cream</category></categories>` +
+				`<offers><offer id="0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21" available="true"><name>Milk &lt;3.2%&gt;</name><url>https://shop.ru/product/0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21</url>` +
+				`<price>80.90</price><oldprice>89.90</oldprice><currencyId>RUB</currencyId><categoryId>6c1f5e3f-9e2d-4b66-8e1b-1c8b3d4d0a21</categoryId>` +
+				`<picture>https://shop.ru/uploads/products/2.jpg</picture><picture>https://shop.ru/uploads/products/1.jpg</picture><picture>https://cdn.ru/milk.jpg</picture>` +
+				`<weight>1.05</weight><param name="brand">Farm</param><param name="fat">3.2</param></offer></offers></shop></yml_catalog>`,
+		},
+		{
+			format: FormatGoogle,
+			feed: xmlHeader + `<rss version="2.0" xmlns:g="http://base.google.com/ns/1.0"><channel><title>Market</title><link>https://shop.ru</link><description>ООО &#34;Маркет&#34;</description>` +
+				`<item><g:id>0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21</g:id><title>Milk &lt;3.2%&gt;</title><description>Milk &lt;3.2%&gt;</description><link>https://shop.ru/product/0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21</link>` +
+				`<g:image_link>https://shop.ru/uploads/products/2.jpg</g:image_link><g:additional_image_link>https://shop.ru/uploads/products/1.jpg</g:additional_image_link><g:additional_image_link>https://cdn.ru/milk.jpg</g:additional_image_link>` +
+				`<g:price>89.90 RUB</g:price><g:sale_price>80.90 RUB</g:sale_price><g:availability>in_stock</g:availability><g:condition>new</g:condition><g:product_type>food &gt; milk &amp; cream</g:product_type>` +
+				`<g:identifier_exists>no</g:identifier_exists><g:shipping_weight>1.05 kg</g:shipping_weight></item></channel></rss>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := g.NewWriter(&buf, tt.format, categories, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+			assert.Equal(t, err, nil)
+			for i := range products {
+				assert.Equal(t, w.Write(&products[i]), nil)
+			}
+			assert.Equal(t, w.Close(), nil)
+			assert.Equal(t, buf.String(), tt.feed)
+		})
+	}
+	_, err = g.NewWriter(&bytes.Buffer{}, "csv", categories, time.Now())
+	assert.Equal(t, err, ErrFormat)
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
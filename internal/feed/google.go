@@ -0,0 +1,105 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/EMus88/Market/internal/models"
+)
+
+//namespace of Google Merchant attributes
+const googleNamespace = "http://base.google.com/ns/1.0"
+
+//item of Google Merchant RSS feed
+type googleItem struct {
+	XMLName              xml.Name `xml:"item"`
+	ID                   string   `xml:"g:id"`
+	Title                string   `xml:"title"`
+	Description          string   `xml:"description"`
+	Link                 string   `xml:"link"`
+	ImageLink            string   `xml:"g:image_link,omitempty"`
+	AdditionalImageLinks []string `xml:"g:additional_image_link"`
+	Price                string   `xml:"g:price"`
+	SalePrice            string   `xml:"g:sale_price,omitempty"`
+	Availability         string   `xml:"g:availability"`
+	Condition            string   `xml:"g:condition"`
+	ProductType          string   `xml:"g:product_type"`
+	IdentifierExists     string   `xml:"g:identifier_exists"`
+	ShippingWeight       string   `xml:"g:shipping_weight"`
+}
+
+type googleWriter struct {
+	g    *Generator
+	tree *tree
+	enc  *xml.Encoder
+}
+
+//rss channel with shop, items are written after it
+func (g *Generator) newGoogle(w io.Writer, t *tree) (*googleWriter, error) {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+	enc := xml.NewEncoder(w)
+	start := []xml.StartElement{
+		{Name: xml.Name{Local: "rss"}, Attr: []xml.Attr{
+			{Name: xml.Name{Local: "version"}, Value: "2.0"},
+			{Name: xml.Name{Local: "xmlns:g"}, Value: googleNamespace},
+		}},
+		{Name: xml.Name{Local: "channel"}},
+	}
+	for _, element := range start {
+		if err := enc.EncodeToken(element); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range []struct{ name, value string }{{"title", g.shop.Name}, {"link", g.shop.URL}, {"description", g.shop.Company}} {
+		if err := enc.EncodeElement(field.value, xml.StartElement{Name: xml.Name{Local: field.name}}); err != nil {
+			return nil, err
+		}
+	}
+	return &googleWriter{g: g, tree: t, enc: enc}, nil
+}
+
+func (w *googleWriter) Write(product *models.ProductDTO) error {
+	if _, ok := w.tree.byName[product.Category]; !ok {
+		return nil
+	}
+	item := googleItem{
+		ID:               product.ID,
+		Title:            product.Name,
+		Description:      product.Description,
+		Link:             w.g.link(product),
+		Price:            fmt.Sprintf("%s %s", product.Price.String(), product.Price.Currency),
+		Availability:     "out_of_stock",
+		Condition:        "new",
+		ProductType:      w.tree.path(product.Category),
+		IdentifierExists: "no",
+		ShippingWeight:   strconv.FormatFloat(product.Weight, 'f', -1, 64) + " kg",
+	}
+	if product.Discount != nil {
+		item.SalePrice = fmt.Sprintf("%s %s", product.Discount.String(), product.Discount.Currency)
+	}
+	//description is required by Google
+	if item.Description == "" {
+		item.Description = product.Name
+	}
+	if product.InStock {
+		item.Availability = "in_stock"
+	}
+	if pictures := w.g.pictures(product); len(pictures) > 0 {
+		item.ImageLink = pictures[0]
+		item.AdditionalImageLinks = pictures[1:]
+	}
+	return w.enc.Encode(item)
+}
+
+func (w *googleWriter) Close() error {
+	for _, name := range []string{"channel", "rss"} {
+		if err := w.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return w.enc.Flush()
+}
@@ -0,0 +1,134 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/EMus88/Market/internal/models"
+)
+
+//offer of Yandex Market Language
+type ymlOffer struct {
+	XMLName     xml.Name `xml:"offer"`
+	ID          string   `xml:"id,attr"`
+	Available   bool     `xml:"available,attr"`
+	Name        string   `xml:"name"`
+	URL         string   `xml:"url"`
+	Price       string   `xml:"price"`
+	OldPrice    string   `xml:"oldprice,omitempty"`
+	CurrencyID  string   `xml:"currencyId"`
+	CategoryID  string   `xml:"categoryId"`
+	Pictures    []string `xml:"picture"`
+	Description string   `xml:"description,omitempty"`
+	Weight      float64  `xml:"weight"`
+	Params      []param  `xml:"param"`
+}
+
+type param struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type ymlCategories struct {
+	XMLName    xml.Name      `xml:"categories"`
+	Categories []ymlCategory `xml:"category"`
+}
+
+type ymlCategory struct {
+	ID       string `xml:"id,attr"`
+	ParentID string `xml:"parentId,attr,omitempty"`
+	Name     string `xml:",chardata"`
+}
+
+type ymlCurrencies struct {
+	XMLName    xml.Name      `xml:"currencies"`
+	Currencies []ymlCurrency `xml:"currency"`
+}
+
+type ymlCurrency struct {
+	ID   string `xml:"id,attr"`
+	Rate string `xml:"rate,attr"`
+}
+
+type ymlWriter struct {
+	g    *Generator
+	tree *tree
+	enc  *xml.Encoder
+}
+
+//yml_catalog with shop, currency and categories, offers are written after them
+func (g *Generator) newYML(w io.Writer, t *tree, at time.Time) (*ymlWriter, error) {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+	enc := xml.NewEncoder(w)
+	start := []xml.StartElement{
+		{Name: xml.Name{Local: "yml_catalog"}, Attr: []xml.Attr{{Name: xml.Name{Local: "date"}, Value: at.Format(time.RFC3339)}}},
+		{Name: xml.Name{Local: "shop"}},
+	}
+	for _, element := range start {
+		if err := enc.EncodeToken(element); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range []struct{ name, value string }{{"name", g.shop.Name}, {"company", g.shop.Company}, {"url", g.shop.URL}} {
+		if err := enc.EncodeElement(field.value, xml.StartElement{Name: xml.Name{Local: field.name}}); err != nil {
+			return nil, err
+		}
+	}
+	currencies := ymlCurrencies{Currencies: []ymlCurrency{{ID: models.BaseCurrency, Rate: "1"}}}
+	if err := enc.Encode(currencies); err != nil {
+		return nil, err
+	}
+	categories := ymlCategories{Categories: make([]ymlCategory, len(t.categories))}
+	for i, category := range t.categories {
+		categories.Categories[i] = ymlCategory{ID: category.ID.String(), Name: category.Name}
+		if category.ParentID != nil {
+			categories.Categories[i].ParentID = category.ParentID.String()
+		}
+	}
+	if err := enc.Encode(categories); err != nil {
+		return nil, err
+	}
+	if err := enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "offers"}}); err != nil {
+		return nil, err
+	}
+	return &ymlWriter{g: g, tree: t, enc: enc}, nil
+}
+
+//products of categories which are not in feed are skipped
+func (w *ymlWriter) Write(product *models.ProductDTO) error {
+	category, ok := w.tree.byName[product.Category]
+	if !ok {
+		return nil
+	}
+	offer := ymlOffer{
+		ID:          product.ID,
+		Available:   product.InStock,
+		Name:        product.Name,
+		URL:         w.g.link(product),
+		Price:       product.Price.String(),
+		CurrencyID:  product.Price.Currency,
+		CategoryID:  category.ID.String(),
+		Pictures:    w.g.pictures(product),
+		Description: product.Description,
+		Weight:      product.Weight,
+		Params:      params(product.Attributes),
+	}
+	//discounted price is price of offer, old price is shown crossed out
+	if product.Discount != nil {
+		offer.Price = product.Discount.String()
+		offer.OldPrice = product.Price.String()
+	}
+	return w.enc.Encode(offer)
+}
+
+func (w *ymlWriter) Close() error {
+	for _, name := range []string{"offers", "shop", "yml_catalog"} {
+		if err := w.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return w.enc.Flush()
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Show full catalog
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion View all products of catalog including hidden, page by page
+// @Accept json
+// @Produce json
+// @Param category query string false "Category with subcategories"
+// @Param min_price query string false "Min price in RUB, for example 99.90"
+// @Param max_price query string false "Max price in RUB, for example 99.90"
+// @Param min_weight query number false "Min weight"
+// @Param max_weight query number false "Max weight"
+// @Param min_valume query number false "Min valume"
+// @Param max_valume query number false "Max valume"
+// @Param only_in_stock query bool false "Only products in stock"
+// @Param attr query string false "Attribute filter as attr[name]=value or attr[name]=min..max"
+// @Param sort query string false "Sort order" Enums(name, price, price_desc, newest)
+// @Param limit query int false "Products on page, 20 by default"
+// @Param cursor query string false "Next cursor from previous page"
+// @Success 200 {object} models.AdminCatalogPage
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/all [get]
+func (h *Handler) GetFullCatalog(c *gin.Context) {
+	var filter models.CatalogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if !h.attributeFilters(c, &filter.ProductFilter) {
+		return
+	}
+	catalog, err := h.service.Repository.GetCatalog(&filter, true)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, models.AdminCatalogPage{
+		Products:   adminProducts(catalog.Products),
+		NextCursor: catalog.NextCursor,
+		Total:      catalog.Total,
+	})
+}
+
+// @Summary Search in full catalog
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion Search products in catalog including hidden
+// @Accept json
+// @Produce json
+// @Param category query string false "Category"
+// @Param product query string true "Product"
+// @Param min_price query string false "Min price in RUB, for example 99.90"
+// @Param max_price query string false "Max price in RUB, for example 99.90"
+// @Param min_weight query number false "Min weight"
+// @Param max_weight query number false "Max weight"
+// @Param min_valume query number false "Min valume"
+// @Param max_valume query number false "Max valume"
+// @Param only_in_stock query bool false "Only products in stock"
+// @Param attr query string false "Attribute filter as attr[name]=value or attr[name]=min..max"
+// @Success 200 {object} models.AdminSearchResult
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/search/all [get]
+func (h *Handler) SearchAll(c *gin.Context) {
+	result, ok := h.search(c, true)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.AdminSearchResult{
+		Products: adminProducts(result.Products),
+		Facets:   result.Facets,
+	})
+}
+
+//add visible state to products
+func adminProducts(products []models.ProductDTO) []models.AdminProductDTO {
+	result := make([]models.AdminProductDTO, 0, len(products))
+	for _, product := range products {
+		result = append(result, models.AdminProductDTO{ProductDTO: product, Visible: product.Visible})
+	}
+	return result
+}
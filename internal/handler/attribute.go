@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// @Summary Show attributes of category
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion View attributes of category including attributes of parent categories
+// @Accept json
+// @Produce json
+// @Param id path string true "category id"
+// @Success 200 {array} models.CategoryAttribute
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/category/{id}/attributes [get]
+func (h *Handler) GetAttributes(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	attributes, err := h.service.Repository.GetAttributes(id)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, attributes)
+}
+
+// @Summary Add attribute to category
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion add attribute to schema of category and its subcategories
+// @Accept json
+// @Produce json
+// @Param id path string true "category id"
+// @Param input body models.CategoryAttribute true "attribute with type number, string or bool"
+// @Success 200 {object} models.CategoryAttribute
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Attribute already exist"
+// @Failure 500 "Internal server error"
+// @Router /catalog/category/{id}/attributes [post]
+func (h *Handler) AddAttribute(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var attribute models.CategoryAttribute
+	if err := c.ShouldBindJSON(&attribute); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	attribute.CategoryID = id
+	if err := h.service.Repository.AddAttribute(&attribute); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, attribute)
+}
+
+// @Summary Delete attribute of category
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion delete attribute from schema of category, values of products are kept
+// @Accept json
+// @Produce json
+// @Param id path string true "category id"
+// @Param name path string true "attribute name"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal server error"
+// @Router /catalog/category/{id}/attributes/{name} [delete]
+func (h *Handler) DeleteAttribute(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.DeleteAttribute(id, c.Param("name")); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+//parse attribute filters like attr[color]=black or attr[screen]=40..55
+func (h *Handler) attributeFilters(c *gin.Context, f *models.ProductFilter) bool {
+	values := c.QueryMap("attr")
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	//same order of arguments for same query
+	sort.Strings(names)
+	for _, name := range names {
+		filter, err := parseAttributeFilter(name, values[name])
+		if err != nil {
+			h.logger.Error(err)
+			c.Status(http.StatusBadRequest)
+			return false
+		}
+		f.Attributes = append(f.Attributes, filter)
+	}
+	return true
+}
+
+func parseAttributeFilter(name, value string) (models.AttributeFilter, error) {
+	filter := models.AttributeFilter{Name: name}
+	i := strings.Index(value, "..")
+	if i < 0 {
+		filter.Value = &value
+		return filter, nil
+	}
+	//range of number attribute, one of bounds can be omitted
+	var err error
+	if filter.Min, err = parseBound(value[:i]); err != nil {
+		return filter, err
+	}
+	if filter.Max, err = parseBound(value[i+2:]); err != nil {
+		return filter, err
+	}
+	if filter.Min == nil && filter.Max == nil {
+		return filter, errors.New("error: empty range of attribute")
+	}
+	return filter, nil
+}
+
+func parseBound(s string) (*float64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -22,14 +22,27 @@ func (h *Handler) AuthMiddleware(c *gin.Context) {
 	}
 	bearerToken := authHeader[1]
 	//validate token
-	if _, _, err := h.service.ValidateToken(bearerToken, "access"); err != nil {
+	id, role, err := h.service.ValidateToken(bearerToken, "access")
+	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
 		c.Abort()
 		return
 	}
+	//claims of user for next handlers
+	c.Set(userIDKey, id)
+	c.Set(roleKey, role)
 	c.Next()
 }
 
+//id of user from token, nil if it is not valid uuid
+func userID(c *gin.Context) *uuid.UUID {
+	id, err := uuid.FromString(c.GetString(userIDKey))
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
 func (h *Handler) IsAdminMiddleware(c *gin.Context) {
 	//getting a token
 	authHeader := strings.Split(c.GetHeader("Authorization"), " ")
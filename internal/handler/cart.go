@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// @Summary Show cart
+// @Security ApiKeyAuth
+// @Tags cart
+// @Descriotion View cart of user with totals, lines are checked for hidden products, stock and price changes
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Cart
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 500 "Internal server error"
+// @Router /cart [get]
+func (h *Handler) GetCart(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	cart, err := h.service.GetCart(*user)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// @Summary Add product to cart
+// @Security ApiKeyAuth
+// @Tags cart
+// @Descriotion add product or its variant to cart, quantity is increased if it is in cart already
+// @Accept json
+// @Produce json
+// @Param input body models.CartItemDTO true "product and quantity"
+// @Success 200 {object} models.Cart
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Product not found"
+// @Failure 500 "Internal server error"
+// @Router /cart/items [post]
+func (h *Handler) AddToCart(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	//bindig request
+	var item models.CartItemDTO
+	if err := c.ShouldBindJSON(&item); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.AddToCart(*user, &item); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	h.GetCart(c)
+}
+
+// @Summary Change quantity in cart
+// @Security ApiKeyAuth
+// @Tags cart
+// @Descriotion set quantity of cart line
+// @Accept json
+// @Produce json
+// @Param id path string true "cart line id"
+// @Param input body models.CartQuantity true "new quantity"
+// @Success 200 {object} models.Cart
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal server error"
+// @Router /cart/items/{id} [patch]
+func (h *Handler) UpdateCartItem(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var quantity models.CartQuantity
+	if err := c.ShouldBindJSON(&quantity); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.UpdateCartItem(*user, id, quantity.Quantity); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	h.GetCart(c)
+}
+
+// @Summary Remove product from cart
+// @Security ApiKeyAuth
+// @Tags cart
+// @Descriotion delete cart line
+// @Accept json
+// @Produce json
+// @Param id path string true "cart line id"
+// @Success 200 {object} models.Cart
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal server error"
+// @Router /cart/items/{id} [delete]
+func (h *Handler) DeleteCartItem(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.DeleteCartItem(*user, id); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	h.GetCart(c)
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// @Summary Show categories
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion View visible categories of catalog
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Category
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/category [get]
+func (h *Handler) GetCategories(c *gin.Context) {
+	categories, err := h.service.Repository.GetCategories(false)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// @Summary Show all categories
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion View all categories of catalog including hidden
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Category
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/category/all [get]
+func (h *Handler) GetAllCategories(c *gin.Context) {
+	categories, err := h.service.Repository.GetCategories(true)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// @Summary Rename category
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion rename category by id
+// @Accept json
+// @Produce json
+// @Param id path string true "category id"
+// @Param input body models.Category true "new name of category"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Category already exist"
+// @Failure 500 "Internal server error"
+// @Router /catalog/category/{id} [patch]
+func (h *Handler) RenameCategory(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var category models.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.RenameCategory(id, category.Name); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Delete category
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion delete category by id, category with products can not be deleted
+// @Accept json
+// @Produce json
+// @Param id path string true "category id"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Category has products"
+// @Failure 500 "Internal server error"
+// @Router /catalog/category/{id} [delete]
+func (h *Handler) DeleteCategory(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.DeleteCategory(id); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Change category visible
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion Change categories visible in catalog
+// @Accept json
+// @Produce json
+// @Param input body models.Visible true "name of category"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/category/change [put]
+func (h *Handler) ChangeCategoryVisible(c *gin.Context) {
+	//bindig request
+	var visible models.Visible
+	if err := c.ShouldBindJSON(&visible); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.ChangeCategoryVisible(&visible); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Move category
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion move category with subcategories to another parent, null parent makes it root
+// @Accept json
+// @Produce json
+// @Param id path string true "category id"
+// @Param input body models.CategoryMove true "new parent of category"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Category can not be moved into itself"
+// @Failure 500 "Internal server error"
+// @Router /catalog/category/{id}/move [put]
+func (h *Handler) MoveCategory(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var move models.CategoryMove
+	if err := c.ShouldBindJSON(&move); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.MoveCategory(id, move.ParentID); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Show categories tree
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion View tree of visible categories
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.CategoryNode
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/tree [get]
+func (h *Handler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.service.Repository.GetCategoryTree(nil)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, tree)
+}
+
+// @Summary Show categories subtree
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion View subtree of visible categories starting from category
+// @Accept json
+// @Produce json
+// @Param id path string true "category id"
+// @Success 200 {object} models.CategoryNode
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal server error"
+// @Router /catalog/tree/{id} [get]
+func (h *Handler) GetCategorySubtree(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	tree, err := h.service.Repository.GetCategoryTree(&id)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, tree[0])
+}
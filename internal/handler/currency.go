@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+//convert prices to currency from query, prices are left in base currency by default
+func (h *Handler) convertPrices(c *gin.Context, products []models.ProductDTO) bool {
+	currency := strings.ToUpper(c.Query("currency"))
+	if currency == "" || currency == models.BaseCurrency {
+		return true
+	}
+	if !models.IsCurrency(currency) {
+		c.Status(http.StatusBadRequest)
+		return false
+	}
+	if err := h.service.Repository.ConvertPrices(products, currency); err != nil {
+		c.Status(errorStatus(err))
+		return false
+	}
+	return true
+}
+
+// @Summary Set product price in currency
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion set explicit price of product in not base currency instead of conversion by rate
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param input body models.Money true "price"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/price [put]
+func (h *Handler) SetProductPrice(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var price models.Money
+	if err := c.ShouldBindJSON(&price); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if price.Amount <= 0 || price.Currency == models.BaseCurrency {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.SetProductPrice(id, price); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Delete product price in currency
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion delete explicit price of product, price will be converted by rate
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param currency path string true "currency"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/price/{currency} [delete]
+func (h *Handler) DeleteProductPrice(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.DeleteProductPrice(id, strings.ToUpper(c.Param("currency"))); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Show exchange rates
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion units of currency for one ruble
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.ExchangeRate
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/rates [get]
+func (h *Handler) GetRates(c *gin.Context) {
+	rates, err := h.service.Repository.GetRates()
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+// @Summary Set exchange rate
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion set units of currency for one ruble
+// @Accept json
+// @Produce json
+// @Param input body models.ExchangeRate true "rate"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/rates [put]
+func (h *Handler) SetRate(c *gin.Context) {
+	//bindig request
+	var rate models.ExchangeRate
+	if err := c.ShouldBindJSON(&rate); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	rate.Currency = strings.ToUpper(rate.Currency)
+	if !models.IsCurrency(rate.Currency) || rate.Currency == models.BaseCurrency {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if _, err := models.ParseRate(rate.Rate); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.SetRate(&rate); err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/EMus88/Market/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Export catalog
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion download all products including hidden ones with ids, categories, variants and stock, file is streamed
+// @Produce octet-stream
+// @Param format query string false "csv (by default), xlsx or jsonl"
+// @Success 200 {file} file "Catalog file"
+// @Failure 400 "Unknown format"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/export [get]
+func (h *Handler) ExportProducts(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if err := service.ExportFormat(format); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Type", service.ExportContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="catalog-%s.%s"`, time.Now().Format("20060102"), format))
+	if err := h.service.ExportProducts(c.Request.Context(), c.Writer, format); err != nil {
+		//status can't be changed when part of file is sent already
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			c.Status(errorStatus(err))
+			return
+		}
+		h.logger.Error(err)
+		c.Abort()
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/EMus88/Market/internal/feed"
+	"github.com/EMus88/Market/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Yandex Market feed
+// @Tags feeds
+// @Descriotion YML offers of visible catalog with shop metadata from config, feed is cached
+// @Produce xml
+// @Success 200 {file} file "YML catalog"
+// @Success 304 "Not modified"
+// @Failure 404 "Feeds are not configured"
+// @Failure 500 "Internal server error"
+// @Router /feeds/yandex.yml [get]
+func (h *Handler) YandexFeed(c *gin.Context) {
+	h.serveFeed(c, feed.FormatYML, "yandex.yml")
+}
+
+// @Summary Google Merchant feed
+// @Tags feeds
+// @Descriotion RSS items of visible catalog for Google Merchant Center, feed is cached
+// @Produce xml
+// @Success 200 {file} file "RSS feed"
+// @Success 304 "Not modified"
+// @Failure 404 "Feeds are not configured"
+// @Failure 500 "Internal server error"
+// @Router /feeds/google.xml [get]
+func (h *Handler) GoogleFeed(c *gin.Context) {
+	h.serveFeed(c, feed.FormatGoogle, "google.xml")
+}
+
+//feed with Last-Modified of its generation, so marketplace can download only changed feed
+func (h *Handler) serveFeed(c *gin.Context, format, name string) {
+	f, err := h.service.Feed(c.Request.Context(), format)
+	if err != nil {
+		if errors.Is(err, service.ErrFeed) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Header("Content-Type", "application/xml; charset=utf-8")
+	http.ServeContent(c.Writer, c.Request, name, f.Generated, bytes.NewReader(f.Data))
+}
@@ -1,14 +1,21 @@
 package handler
 
 import (
+	"errors"
 	"math"
 	"net/http"
+	"strings"
 
 	_ "github.com/EMus88/Market/docs"
 	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/payment"
+	"github.com/EMus88/Market/internal/repository"
 	"github.com/EMus88/Market/internal/service"
+	"github.com/EMus88/Market/internal/shipping"
+	"github.com/EMus88/Market/internal/storage"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
 	"github.com/sirupsen/logrus"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/swaggo/gin-swagger/swaggerFiles"
@@ -16,9 +23,16 @@ import (
 
 const userRole = "user"
 
+//keys of token claims in context
+const (
+	userIDKey = "userID"
+	roleKey   = "role"
+)
+
 type Handler struct {
-	service *service.Service
-	logger  *logrus.Logger
+	service    *service.Service
+	Storefront Storefront
+	logger     *logrus.Logger
 }
 
 func NewHandler(service *service.Service, logger *logrus.Logger) *Handler {
@@ -49,14 +63,141 @@ func (h *Handler) Init() *gin.Engine {
 	{
 		//add category
 		catalog.POST("/category", h.IsAdminMiddleware, h.AddCategory)
+		//get visible categories
+		catalog.GET("/category", h.GetCategories)
+		//get all categories including hidden
+		catalog.GET("/category/all", h.IsAdminMiddleware, h.GetAllCategories)
+		//rename and delete category by id
+		catalog.PATCH("/category/:id", h.IsAdminMiddleware, h.RenameCategory)
+		catalog.DELETE("/category/:id", h.IsAdminMiddleware, h.DeleteCategory)
+		//change categories visible in catalog
+		catalog.PUT("/category/change", h.IsAdminMiddleware, h.ChangeCategoryVisible)
+		//move category with subcategories to another parent
+		catalog.PUT("/category/:id/move", h.IsAdminMiddleware, h.MoveCategory)
+		//attributes schema of category
+		catalog.GET("/category/:id/attributes", h.GetAttributes)
+		catalog.POST("/category/:id/attributes", h.IsAdminMiddleware, h.AddAttribute)
+		catalog.DELETE("/category/:id/attributes/:name", h.IsAdminMiddleware, h.DeleteAttribute)
+		//get categories tree or subtree
+		catalog.GET("/tree", h.GetCategoryTree)
+		catalog.GET("/tree/:id", h.GetCategorySubtree)
 		//add product
 		catalog.POST("/product", h.IsAdminMiddleware, h.AddProduct)
+		//create and update products from csv or xlsx file
+		catalog.POST("/import", h.IsAdminMiddleware, h.ImportProducts)
+		//download all products as csv, xlsx or json lines
+		catalog.GET("/export", h.IsAdminMiddleware, h.ExportProducts)
 		//change products visible in catalog
 		catalog.PUT("/product/change", h.IsAdminMiddleware, h.ChangeVisible)
+		//get, update and delete product by id
+		catalog.GET("/product/:id", h.IsAdminMiddleware, h.GetProduct)
+		catalog.PATCH("/product/:id", h.IsAdminMiddleware, h.UpdateProduct)
+		catalog.DELETE("/product/:id", h.IsAdminMiddleware, h.DeleteProduct)
+		//prices of product in other currencies
+		catalog.PUT("/product/:id/price", h.IsAdminMiddleware, h.SetProductPrice)
+		catalog.DELETE("/product/:id/price/:currency", h.IsAdminMiddleware, h.DeleteProductPrice)
+		//history of price and scheduled prices
+		catalog.GET("/product/:id/prices/history", h.IsAdminMiddleware, h.GetPriceHistory)
+		catalog.GET("/product/:id/prices/at", h.IsAdminMiddleware, h.GetPriceAt)
+		catalog.GET("/product/:id/prices/scheduled", h.IsAdminMiddleware, h.GetScheduledPrices)
+		catalog.POST("/product/:id/prices/scheduled", h.IsAdminMiddleware, h.AddScheduledPrice)
+		catalog.DELETE("/product/:id/prices/scheduled/:schedule", h.IsAdminMiddleware, h.DeleteScheduledPrice)
+		//uploaded images of product
+		catalog.GET("/product/:id/images", h.GetImages)
+		catalog.POST("/product/:id/images", h.IsAdminMiddleware, h.UploadImages)
+		catalog.PUT("/product/:id/images", h.IsAdminMiddleware, h.SortImages)
+		catalog.PUT("/product/:id/images/:image/primary", h.IsAdminMiddleware, h.SetPrimaryImage)
+		catalog.DELETE("/product/:id/images/:image", h.IsAdminMiddleware, h.DeleteImage)
+		//variants of product
+		catalog.POST("/product/:id/variants", h.IsAdminMiddleware, h.AddVariant)
+		catalog.PATCH("/product/:id/variants/:variant", h.IsAdminMiddleware, h.UpdateVariant)
+		catalog.DELETE("/product/:id/variants/:variant", h.IsAdminMiddleware, h.DeleteVariant)
+		//warehouses and stock ledger
+		catalog.GET("/warehouses", h.IsAdminMiddleware, h.GetWarehouses)
+		catalog.POST("/warehouses", h.IsAdminMiddleware, h.AddWarehouse)
+		catalog.POST("/stock", h.IsAdminMiddleware, h.AdjustStock)
+		catalog.GET("/stock/movements", h.IsAdminMiddleware, h.GetMovements)
+		catalog.GET("/product/:id/stock", h.IsAdminMiddleware, h.GetStock)
+		//exchange rates
+		catalog.GET("/rates", h.GetRates)
+		catalog.PUT("/rates", h.IsAdminMiddleware, h.SetRate)
 		//get all catalog
 		catalog.GET("/", h.GetCatalog)
 		//search
 		catalog.GET("/search", h.Search)
+		//autocomplete
+		catalog.GET("/suggest", h.Suggest)
+		//get all catalog and search including hidden products
+		catalog.GET("/all", h.IsAdminMiddleware, h.GetFullCatalog)
+		catalog.GET("/search/all", h.IsAdminMiddleware, h.SearchAll)
+	}
+
+	//cart of user from token
+	cart := router.Group("/cart").Use(h.AuthMiddleware)
+	{
+		cart.GET("", h.GetCart)
+		cart.POST("/items", h.AddToCart)
+		cart.PATCH("/items/:id", h.UpdateCartItem)
+		cart.DELETE("/items/:id", h.DeleteCartItem)
+		cart.GET("/shipping", h.QuoteCart)
+		cart.POST("/promo", h.SetCartPromo)
+		cart.DELETE("/promo", h.DeleteCartPromo)
+	}
+
+	//orders of user and management of all orders
+	orders := router.Group("/orders").Use(h.AuthMiddleware)
+	{
+		orders.POST("", h.Checkout)
+		orders.GET("", h.GetOrders)
+		orders.GET("/:id", h.GetOrder)
+		orders.POST("/:id/cancel", h.CancelOrder)
+		orders.GET("/all", h.IsAdminMiddleware, h.GetAllOrders)
+		orders.GET("/all/:id", h.IsAdminMiddleware, h.GetAnyOrder)
+		orders.PUT("/:id/status", h.IsAdminMiddleware, h.ChangeOrderStatus)
+		orders.POST("/:id/pay", h.PayOrder)
+		orders.GET("/:id/shipping", h.QuoteOrder)
+		orders.POST("/:id/refund", h.IsAdminMiddleware, h.RefundOrder)
+	}
+
+	//promo codes and automatic discounts
+	promos := router.Group("/promos").Use(h.AuthMiddleware, h.IsAdminMiddleware)
+	{
+		promos.GET("", h.GetPromos)
+		promos.POST("", h.AddPromo)
+		promos.GET("/:id", h.GetPromo)
+		promos.PUT("/:id", h.UpdatePromo)
+		promos.DELETE("/:id", h.DeletePromo)
+	}
+
+	//events of payment provider are checked by signature
+	payments := router.Group("/payments")
+	{
+		payments.POST("/webhook", h.PaymentWebhook)
+		payments.POST("/fake/:intent/pay", h.FakePay)
+	}
+
+	//visible catalog for anonymous visitors, it is enabled in config
+	if h.Storefront.Enabled {
+		store := router.Group("/store").Use(h.StoreCache)
+		{
+			store.GET("/catalog", h.StoreCatalog)
+			store.GET("/search", h.StoreSearch)
+			store.GET("/suggest", h.StoreSuggest)
+			store.GET("/tree", h.StoreTree)
+			store.GET("/product/:id", h.StoreProduct)
+		}
+	}
+
+	//feeds of catalog for marketplaces are public
+	feeds := router.Group("/feeds")
+	{
+		feeds.GET("/yandex.yml", h.YandexFeed)
+		feeds.GET("/google.xml", h.GoogleFeed)
+	}
+
+	//files of local storage, other storages give their own urls
+	if local, ok := h.service.Storage.(*storage.Local); ok {
+		router.Static(local.Path, local.Dir)
 	}
 
 	router.NoRoute(func(c *gin.Context) {
@@ -117,8 +258,15 @@ func (h *Handler) AddProduct(c *gin.Context) {
 		c.Status(http.StatusBadRequest)
 		return
 	}
+	if !validPrice(product.Price) {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.CheckAttributes(product.Category, product.Attributes); err != nil {
+		h.validationError(c, err)
+		return
+	}
 	//Round float to 2 decimal places
-	product.Price = math.Round(product.Price*100) / 100
 	product.Weight = math.Round(product.Weight*100) / 100
 	product.Valume = math.Round(product.Valume*100) / 100
 	if err := h.service.Repository.AddProduct(&product); err != nil {
@@ -127,6 +275,115 @@ func (h *Handler) AddProduct(c *gin.Context) {
 	}
 }
 
+// @Summary Get product
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion get product by id
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Success 200 {object} models.ProductDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id} [get]
+func (h *Handler) GetProduct(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	product, err := h.service.Repository.GetProduct(id, true)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	products := []models.ProductDTO{*product}
+	if !h.discountPrices(c, products) {
+		return
+	}
+	c.JSON(http.StatusOK, products[0])
+}
+
+// @Summary Update product
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion update some fields of product by id
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param input body models.ProductUpdate true "changed fields"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id} [patch]
+func (h *Handler) UpdateProduct(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var product models.ProductUpdate
+	if err := c.ShouldBindJSON(&product); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if product.Price != nil && !validPrice(*product.Price) {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.CheckProductUpdate(id, &product); err != nil {
+		h.validationError(c, err)
+		return
+	}
+	//Round float to 2 decimal places
+	if product.Weight != nil {
+		*product.Weight = math.Round(*product.Weight*100) / 100
+	}
+	if product.Valume != nil {
+		*product.Valume = math.Round(*product.Valume*100) / 100
+	}
+	if err := h.service.Repository.UpdateProduct(id, &product, userID(c)); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Delete product
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion delete product by id
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id} [delete]
+func (h *Handler) DeleteProduct(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.DeleteProduct(id); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
 // @Summary Change visible
 // @Security ApiKeyAuth
 // @Tags catalog
@@ -158,17 +415,43 @@ func (h *Handler) ChangeVisible(c *gin.Context) {
 // @Summary Show catalog
 // @Security ApiKeyAuth
 // @Tags catalog
-// @Descriotion View all products of catalog
+// @Descriotion View products of catalog page by page
 // @Accept json
 // @Produce json
-// @Success 200 "Ok"
+// @Param category query string false "Category with subcategories"
+// @Param min_price query string false "Min price in RUB, for example 99.90"
+// @Param max_price query string false "Max price in RUB, for example 99.90"
+// @Param min_weight query number false "Min weight"
+// @Param max_weight query number false "Max weight"
+// @Param min_valume query number false "Min valume"
+// @Param max_valume query number false "Max valume"
+// @Param only_in_stock query bool false "Only products in stock"
+// @Param attr query string false "Attribute filter as attr[name]=value or attr[name]=min..max"
+// @Param sort query string false "Sort order" Enums(name, price, price_desc, newest)
+// @Param limit query int false "Products on page, 20 by default"
+// @Param cursor query string false "Next cursor from previous page"
+// @Param currency query string false "Currency of prices, RUB by default"
+// @Success 200 {object} models.CatalogPage
+// @Failure 400 "Bad request"
 // @Failure 401 {string} json "{"error":"unauthenticated"}"
 // @Failure 500 "Internal server error"
 // @Router /catalog [get]
 func (h *Handler) GetCatalog(c *gin.Context) {
-	catalog, err := h.service.Repository.GetCatalog()
+	var filter models.CatalogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if !h.attributeFilters(c, &filter.ProductFilter) {
+		return
+	}
+	catalog, err := h.service.Repository.GetCatalog(&filter, false)
 	if err != nil {
-		c.Status(http.StatusInternalServerError)
+		c.Status(errorStatus(err))
+		return
+	}
+	if !h.discountPrices(c, catalog.Products) || !h.convertPrices(c, catalog.Products) {
 		return
 	}
 	c.JSON(http.StatusOK, catalog)
@@ -177,39 +460,120 @@ func (h *Handler) GetCatalog(c *gin.Context) {
 // @Summary Search in catalog
 // @Security ApiKeyAuth
 // @Tags catalog
-// @Descriotion Search products in catalog
+// @Descriotion Search products in catalog by name and description, most relevant first, with facets counts
 // @Accept json
 // @Produce json
 // @Param category query string false "Category"
 // @Param product query string true "Product"
-// @Success 200 "Ok"
+// @Param min_price query string false "Min price in RUB, for example 99.90"
+// @Param max_price query string false "Max price in RUB, for example 99.90"
+// @Param min_weight query number false "Min weight"
+// @Param max_weight query number false "Max weight"
+// @Param min_valume query number false "Min valume"
+// @Param max_valume query number false "Max valume"
+// @Param only_in_stock query bool false "Only products in stock"
+// @Param attr query string false "Attribute filter as attr[name]=value or attr[name]=min..max"
+// @Param currency query string false "Currency of prices, RUB by default"
+// @Success 200 {object} models.SearchResult
 // @Failure 400 "Bad request"
 // @Failure 401 {string} json "{"error":"unauthenticated"}"
 // @Failure 500 "Internal server error"
 // @Router /catalog/search [get]
 func (h *Handler) Search(c *gin.Context) {
-	category := c.Query("category")
-	productName := c.Query("product")
+	result, ok := h.search(c, false)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
 
-	if category == "" && productName == "" {
+// @Summary Autocomplete
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion Names of products and categories which start with typed text
+// @Accept json
+// @Produce json
+// @Param q query string true "Typed text"
+// @Success 200 {object} models.Suggestions
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/suggest [get]
+func (h *Handler) Suggest(c *gin.Context) {
+	prefix := strings.TrimSpace(c.Query("q"))
+	if prefix == "" {
 		c.Status(http.StatusBadRequest)
 		return
 	}
-	if category == "" {
-		result, err := h.service.Repository.GetByAllCategories(productName)
-		if err != nil {
-			c.Status(http.StatusInternalServerError)
-			return
-		}
-		c.JSON(http.StatusOK, result)
+	suggestions, err := h.service.Repository.Suggest(strings.ToLower(prefix))
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
 		return
-	} else {
-		result, err := h.service.Repository.GetByCategory(productName, category)
-		if err != nil {
-			c.Status(http.StatusInternalServerError)
-			return
-		}
-		c.JSON(http.StatusOK, result)
 	}
+	c.JSON(http.StatusOK, suggestions)
+}
+
+//search visible products or all products for administrator
+func (h *Handler) search(c *gin.Context, all bool) (*models.SearchResult, bool) {
+	var filter models.SearchFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return nil, false
+	}
+	if filter.Category == "" && filter.Product == "" {
+		c.Status(http.StatusBadRequest)
+		return nil, false
+	}
+	if !h.attributeFilters(c, &filter.ProductFilter) {
+		return nil, false
+	}
+	result, err := h.service.Repository.Search(&filter, all)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return nil, false
+	}
+	if !h.discountPrices(c, result.Products) || !h.convertPrices(c, result.Products) {
+		return nil, false
+	}
+	return result, true
+}
+
+//convert repository error to response status
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrNotFound), errors.Is(err, payment.ErrIntent):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrAlreadyExist), errors.Is(err, repository.ErrInUse), errors.Is(err, repository.ErrCycle),
+		errors.Is(err, repository.ErrNoStock), errors.Is(err, repository.ErrStatusChanged),
+		errors.Is(err, service.ErrTransition), errors.Is(err, service.ErrNotPayable), errors.Is(err, service.ErrNotRefundable),
+		errors.Is(err, payment.ErrState), errors.Is(err, repository.ErrPromoUsed),
+		errors.Is(err, repository.ErrCartChanged):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrNoCategory), errors.Is(err, repository.ErrBadCursor), errors.Is(err, repository.ErrNoRate),
+		errors.Is(err, service.ErrEmptyCart), errors.Is(err, payment.ErrSignature), errors.Is(err, service.ErrPaymentAmount),
+		errors.Is(err, service.ErrPaymentUnknown), errors.Is(err, shipping.ErrNoZone), errors.Is(err, repository.ErrImageSet):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+//write reason of invalid attributes, options, promo, scheduled price or image
+func (h *Handler) validationError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrAttributes) || errors.Is(err, service.ErrOptions) || errors.Is(err, service.ErrPromo) ||
+		errors.Is(err, service.ErrSchedule) || errors.Is(err, service.ErrImage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrImageSize) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(errorStatus(err))
+}
 
+//price of product is positive and set in base currency
+func validPrice(price models.Money) bool {
+	return price.Amount > 0 && price.Currency == models.BaseCurrency
 }
@@ -4,16 +4,27 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/payment"
 	"github.com/EMus88/Market/internal/repository"
 	"github.com/EMus88/Market/internal/service"
+	"github.com/EMus88/Market/internal/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/assert"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/pashagolub/pgxmock"
 	"github.com/sirupsen/logrus"
 )
@@ -69,7 +80,7 @@ func Test_AddCategory(t *testing.T) {
 		AddRow("123")
 
 	mock.ExpectQuery("INSERT INTO categories").
-		WithArgs("food").
+		WithArgs("food", (*uuid.UUID)(nil)).
 		WillReturnRows(Rows)
 
 	//run tests
@@ -94,3 +105,1073 @@ func Test_AddCategory(t *testing.T) {
 	}
 
 }
+
+func Test_GetProduct(t *testing.T) {
+	type want struct {
+		statusCode int
+	}
+	tests := []struct {
+		name string
+		id   string
+		want want
+	}{
+		{
+			name: "Bad request",
+			id:   "123",
+			want: want{statusCode: 400},
+		},
+		{
+			name: "Not found",
+			id:   "5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10",
+			want: want{statusCode: 404},
+		},
+		{
+			name: "Ok",
+			id:   "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21",
+			want: want{statusCode: 200},
+		},
+	}
+	//init logger
+	logger := logrus.New()
+
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+
+	//init main components
+	r := repository.NewRepository(mock, logger)
+	s := service.NewService(r, logger)
+	h := NewHandler(s, logger)
+
+	//set mock
+	mock.ExpectQuery("SELECT (.+) FROM products").
+		WillReturnError(pgx.ErrNoRows)
+
+	Rows := mock.NewRows([]string{"id", "name", "weight", "valume", "description", "photo", "price", "visible", "category", "attributes", "options", "available"}).
+		AddRow("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", "milk", 1.0, 1.0, "", []string{}, int64(8990), true, "food", map[string]interface{}{}, []string{"fat"}, int64(5))
+
+	mock.ExpectQuery("SELECT (.+) FROM products").
+		WillReturnRows(Rows)
+	mock.ExpectQuery("FROM variants").
+		WithArgs([]string{"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"}, true).
+		WillReturnRows(mock.NewRows([]string{"id", "product_id", "options", "weight", "valume", "price", "barcode", "visible", "available"}).
+			AddRow("9d2e1c4a-3b5f-4e6d-8a7b-1c2d3e4f5a6b", "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", map[string]string{"fat": "3.2%"}, 1.0, 1.0, int64(9990), (*string)(nil), true, int64(5)))
+	mock.ExpectQuery("FROM product_images").
+		WithArgs([]string{"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"}).
+		WillReturnRows(mock.NewRows([]string{"product_id", "id", "key", "url", "content_type", "size", "position", "is_primary", "status", "sizes", "files"}).
+			AddRow("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", "5e8a2d1c-7b3f-4a6e-9c8d-2f1e3a4b5c6d", "products/milk.jpg", "/images/products/milk.jpg", "image/jpeg", int64(2048), 0, true,
+				models.ImageReady, map[string]models.ImageSize{"thumb": {URL: "/images/products/milk_thumb.jpg", WebP: "/images/products/milk_thumb.webp", Width: 160, Height: 160}},
+				[]string{"products/milk_thumb.jpg", "products/milk_thumb.webp"}))
+
+	mock.ExpectQuery("FROM promos").
+		WillReturnRows(mock.NewRows([]string{"id", "code", "name", "type", "value", "scope", "targets", "min_total", "starts_at", "ends_at", "usage_limit", "per_user_limit", "used", "stackable", "active"}).
+			AddRow("3c4d5e6f-7a8b-4c9d-8e1f-2a3b4c5d6e7f", "", "milk week", models.DiscountPercent, int64(10), models.ScopeCategory, []string{"food"},
+				int64(0), (*time.Time)(nil), (*time.Time)(nil), (*int64)(nil), (*int64)(nil), int64(0), false, true))
+
+	//run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/catalog/product/"+tt.id, nil)
+			w := httptest.NewRecorder()
+
+			//init router
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.Default()
+			router.GET("/catalog/product/:id", h.GetProduct)
+
+			//sent request
+			router.ServeHTTP(w, req)
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, result.StatusCode, tt.want.statusCode)
+		})
+	}
+
+}
+
+func Test_AddVariant(t *testing.T) {
+	type want struct {
+		statusCode int
+	}
+	id := "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"
+	tests := []struct {
+		name    string
+		variant string
+		visible bool
+		want    want
+	}{
+		{
+			name:    "Bad request",
+			variant: `{"options":{"fat":"3.2%"}}`,
+			want:    want{statusCode: 400},
+		},
+		{
+			name:    "Visible by default",
+			variant: `{"options":{"fat":"3.2%"},"weight":1,"valume":1,"price":{"amount":"99.90","currency":"RUB"}}`,
+			visible: true,
+			want:    want{statusCode: 200},
+		},
+		{
+			name:    "Hidden",
+			variant: `{"options":{"fat":"2.5%"},"weight":1,"valume":1,"price":{"amount":"99.90","currency":"RUB"},"visible":false}`,
+			want:    want{statusCode: 200},
+		},
+	}
+	//init logger
+	logger := logrus.New()
+
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+
+	//init main components
+	r := repository.NewRepository(mock, logger)
+	s := service.NewService(r, logger)
+	h := NewHandler(s, logger)
+
+	//run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//set mock
+			if tt.want.statusCode == 200 {
+				mock.ExpectQuery("SELECT (.+) FROM products").
+					WillReturnRows(mock.NewRows([]string{"id", "name", "weight", "valume", "description", "photo", "price", "visible", "category", "attributes", "options", "available"}).
+						AddRow(id, "milk", 1.0, 1.0, "", []string{}, int64(8990), true, "food", map[string]interface{}{}, []string{"fat"}, int64(5)))
+				mock.ExpectQuery("FROM variants").
+					WillReturnRows(mock.NewRows([]string{"id", "product_id", "options", "weight", "valume", "price", "barcode", "visible", "available"}))
+				mock.ExpectQuery("FROM product_images").
+					WillReturnRows(mock.NewRows([]string{"product_id", "id", "key", "url", "content_type", "size", "position", "is_primary", "status", "sizes", "files"}))
+				mock.ExpectQuery("INSERT INTO variants").
+					WithArgs(uuid.Must(uuid.FromString(id)), pgxmock.AnyArg(), 1.0, 1.0, int64(9990), (*string)(nil), &tt.visible).
+					WillReturnRows(mock.NewRows([]string{"id"}).AddRow("9d2e1c4a-3b5f-4e6d-8a7b-1c2d3e4f5a6b"))
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/catalog/product/"+id+"/variants", bytes.NewBufferString(tt.variant))
+			w := httptest.NewRecorder()
+
+			//init router
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.Default()
+			router.POST("/catalog/product/:id/variants", h.AddVariant)
+
+			//sent request
+			router.ServeHTTP(w, req)
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, result.StatusCode, tt.want.statusCode)
+			assert.Equal(t, mock.ExpectationsWereMet(), nil)
+		})
+	}
+}
+
+func Test_DeleteCategory(t *testing.T) {
+	type want struct {
+		statusCode int
+	}
+	tests := []struct {
+		name string
+		id   string
+		want want
+	}{
+		{
+			name: "Bad request",
+			id:   "books",
+			want: want{statusCode: 400},
+		},
+		{
+			name: "Has products",
+			id:   "5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10",
+			want: want{statusCode: 409},
+		},
+		{
+			name: "Not found",
+			id:   "5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10",
+			want: want{statusCode: 404},
+		},
+		{
+			name: "Ok",
+			id:   "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21",
+			want: want{statusCode: 200},
+		},
+	}
+	//init logger
+	logger := logrus.New()
+
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+
+	//init main components
+	r := repository.NewRepository(mock, logger)
+	s := service.NewService(r, logger)
+	h := NewHandler(s, logger)
+
+	//set mock
+	mock.ExpectQuery("DELETE FROM categories").
+		WillReturnRows(mock.NewRows([]string{"count", "exists"}).AddRow(0, true))
+	mock.ExpectQuery("DELETE FROM categories").
+		WillReturnRows(mock.NewRows([]string{"count", "exists"}).AddRow(0, false))
+	mock.ExpectQuery("DELETE FROM categories").
+		WillReturnRows(mock.NewRows([]string{"count", "exists"}).AddRow(1, true))
+
+	//run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/catalog/category/"+tt.id, nil)
+			w := httptest.NewRecorder()
+
+			//init router
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.Default()
+			router.DELETE("/catalog/category/:id", h.DeleteCategory)
+
+			//sent request
+			router.ServeHTTP(w, req)
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, result.StatusCode, tt.want.statusCode)
+		})
+	}
+
+}
+
+func Test_GetCatalog(t *testing.T) {
+	type want struct {
+		statusCode int
+		body       string
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  want
+	}{
+		{
+			name:  "Bad sort",
+			query: "?sort=color",
+			want:  want{statusCode: 400},
+		},
+		{
+			name:  "Bad cursor",
+			query: "?cursor=123",
+			want:  want{statusCode: 400},
+		},
+		{
+			name:  "Cursor of other sort",
+			query: "?sort=newest&cursor=eyJ2IjoiODk5MCIsImlkIjoiMGM2ZjFiOGUtNjJhNC00YzhmLThjNTUtM2YxZTJhOWI3ZDIxIn0",
+			want:  want{statusCode: 400},
+		},
+		{
+			name:  "Ok",
+			query: "?sort=price&limit=1&min_price=10.5",
+			want: want{
+				statusCode: 200,
+				body:       `{"products":[{"id":"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21","name":"milk","weight":1,"valume":1,"price":{"amount":"89.90","currency":"RUB"},"visible":true,"category":"food","options":["fat"],"variants":[{"id":"9d2e1c4a-3b5f-4e6d-8a7b-1c2d3e4f5a6b","options":{"fat":"3.2%"},"weight":1,"valume":1,"price":{"amount":"99.90","currency":"RUB"},"barcode":"4601234567890","visible":true,"in_stock":true,"available_quantity":3}],"in_stock":true,"available_quantity":3}],"next_cursor":"eyJ2IjoiODk5MCIsImlkIjoiMGM2ZjFiOGUtNjJhNC00YzhmLThjNTUtM2YxZTJhOWI3ZDIxIn0","total":2}`,
+			},
+		},
+	}
+	//init logger
+	logger := logrus.New()
+
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+
+	//init main components
+	r := repository.NewRepository(mock, logger)
+	s := service.NewService(r, logger)
+	h := NewHandler(s, logger)
+
+	//set mock
+	mock.ExpectQuery("SELECT count").
+		WithArgs(int64(1050)).
+		WillReturnRows(mock.NewRows([]string{"count"}).AddRow(2))
+
+	Rows := mock.NewRows([]string{"id", "name", "weight", "valume", "description", "photo", "price", "visible", "category", "attributes", "options", "created_at", "available"}).
+		AddRow("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", "milk", 1.0, 1.0, "", []string{}, int64(8990), true, "food", map[string]interface{}{}, []string{"fat"}, time.Now(), int64(3)).
+		AddRow("5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10", "bread", 1.0, 1.0, "", []string{}, int64(9900), true, "food", map[string]interface{}{}, []string{}, time.Now(), int64(0))
+
+	mock.ExpectQuery("ORDER BY price ASC, products.id ASC").
+		WithArgs(int64(1050)).
+		WillReturnRows(Rows)
+	barcode := "4601234567890"
+	mock.ExpectQuery("FROM variants").
+		WithArgs([]string{"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"}, false).
+		WillReturnRows(mock.NewRows([]string{"id", "product_id", "options", "weight", "valume", "price", "barcode", "visible", "available"}).
+			AddRow("9d2e1c4a-3b5f-4e6d-8a7b-1c2d3e4f5a6b", "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", map[string]string{"fat": "3.2%"}, 1.0, 1.0, int64(9990), &barcode, true, int64(3)))
+	mock.ExpectQuery("FROM product_images").
+		WithArgs([]string{"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"}).
+		WillReturnRows(mock.NewRows([]string{"product_id", "id", "key", "url", "content_type", "size", "position", "is_primary", "status", "sizes", "files"}))
+
+	mock.ExpectQuery("FROM promos").
+		WillReturnRows(mock.NewRows([]string{"id", "code", "name", "type", "value", "scope", "targets", "min_total", "starts_at", "ends_at", "usage_limit", "per_user_limit", "used", "stackable", "active"}))
+
+	//run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/catalog/"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			//init router
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.Default()
+			router.GET("/catalog/", h.GetCatalog)
+
+			//sent request
+			router.ServeHTTP(w, req)
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, result.StatusCode, tt.want.statusCode)
+			if tt.want.body != "" {
+				assert.Equal(t, w.Body.String(), tt.want.body)
+			}
+		})
+	}
+
+}
+
+func Test_Search(t *testing.T) {
+	type want struct {
+		statusCode int
+		body       string
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  want
+	}{
+		{
+			name:  "Bad request",
+			query: "",
+			want:  want{statusCode: 400},
+		},
+		{
+			name:  "Ok",
+			query: "?product=moloko&max_price=100&attr%5Bfat%5D=2..4",
+			want: want{
+				statusCode: 200,
+				body:       `{"products":[{"id":"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21","name":"milk","weight":1,"valume":1,"price":{"amount":"89.90","currency":"RUB"},"visible":true,"category":"food","attributes":{"fat":3.2},"in_stock":false,"available_quantity":0,"highlight":"\u003cb\u003emilk\u003c/b\u003e"}],"facets":{"categories":[{"value":"food","count":1}],"price":[{"to":{"amount":"100.00","currency":"RUB"},"count":1}],"weight":[{"from":1,"to":5,"count":1}],"valume":[{"from":1,"to":5,"count":1}]}}`,
+			},
+		},
+	}
+	//init logger
+	logger := logrus.New()
+
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+
+	//init main components
+	r := repository.NewRepository(mock, logger)
+	s := service.NewService(r, logger)
+	h := NewHandler(s, logger)
+
+	//set mock
+	mock.ExpectBeginTx(pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	mock.ExpectExec("set_config").
+		WithArgs("0.3").
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectQuery("ORDER BY exact DESC").
+		WithArgs("moloko", "молоко", int64(10000), "fat", "fat", 2.0, "fat", "fat", 4.0).
+		WillReturnRows(mock.NewRows([]string{"id", "name", "weight", "valume", "description", "photo", "price", "visible", "category", "attributes", "options", "available", "exact", "rank", "highlight"}).
+			AddRow("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", "milk", 1.0, 1.0, "", []string{}, int64(8990), true, "food", map[string]interface{}{"fat": 3.2}, []string{}, int64(0), false, float32(0.5), "<b>milk</b>"))
+	mock.ExpectQuery("FROM variants").
+		WithArgs([]string{"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"}, false).
+		WillReturnRows(mock.NewRows([]string{"id", "product_id", "options", "weight", "valume", "price", "barcode", "visible", "available"}))
+	mock.ExpectQuery("FROM product_images").
+		WithArgs([]string{"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"}).
+		WillReturnRows(mock.NewRows([]string{"product_id", "id", "key", "url", "content_type", "size", "position", "is_primary", "status", "sizes", "files"}))
+	mock.ExpectQuery("GROUP BY category").
+		WithArgs("moloko", "молоко", int64(10000), "fat", "fat", 2.0, "fat", "fat", 4.0).
+		WillReturnRows(mock.NewRows([]string{"category", "count"}).AddRow("food", 1))
+	mock.ExpectQuery("width_bucket\\(price").
+		WithArgs("moloko", "молоко", "fat", "fat", 2.0, "fat", "fat", 4.0, []int64{10000, 50000, 100000, 500000}).
+		WillReturnRows(mock.NewRows([]string{"bucket", "count"}).AddRow(0, 1))
+	mock.ExpectQuery("width_bucket\\(weight").
+		WithArgs("moloko", "молоко", int64(10000), "fat", "fat", 2.0, "fat", "fat", 4.0, []float64{0.5, 1, 5, 10}).
+		WillReturnRows(mock.NewRows([]string{"bucket", "count"}).AddRow(2, 1))
+	mock.ExpectQuery("width_bucket\\(valume").
+		WithArgs("moloko", "молоко", int64(10000), "fat", "fat", 2.0, "fat", "fat", 4.0, []float64{0.5, 1, 5, 10}).
+		WillReturnRows(mock.NewRows([]string{"bucket", "count"}).AddRow(2, 1))
+	mock.ExpectRollback()
+
+	mock.ExpectQuery("FROM promos").
+		WillReturnRows(mock.NewRows([]string{"id", "code", "name", "type", "value", "scope", "targets", "min_total", "starts_at", "ends_at", "usage_limit", "per_user_limit", "used", "stackable", "active"}))
+
+	//run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/catalog/search"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			//init router
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.Default()
+			router.GET("/catalog/search", h.Search)
+
+			//sent request
+			router.ServeHTTP(w, req)
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, result.StatusCode, tt.want.statusCode)
+			if tt.want.body != "" {
+				assert.Equal(t, w.Body.String(), tt.want.body)
+			}
+		})
+	}
+
+}
+
+func Test_AdjustStock(t *testing.T) {
+	type want struct {
+		statusCode int
+	}
+	warehouse := uuid.Must(uuid.FromString("5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10"))
+	product := uuid.Must(uuid.FromString("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"))
+	tests := []struct {
+		name       string
+		adjustment models.StockAdjustment
+		want       want
+	}{
+		{
+			name:       "Bad request",
+			adjustment: models.StockAdjustment{WarehouseID: warehouse, ProductID: product},
+			want:       want{statusCode: 400},
+		},
+		{
+			name:       "Not enough stock",
+			adjustment: models.StockAdjustment{WarehouseID: warehouse, ProductID: product, Delta: -5},
+			want:       want{statusCode: 409},
+		},
+		{
+			name:       "Ok",
+			adjustment: models.StockAdjustment{WarehouseID: warehouse, ProductID: product, Delta: 10, Reason: "receipt"},
+			want:       want{statusCode: 200},
+		},
+	}
+	//init logger
+	logger := logrus.New()
+
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+
+	//init main components
+	r := repository.NewRepository(mock, logger)
+	s := service.NewService(r, logger)
+	h := NewHandler(s, logger)
+
+	//set mock
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO stocks").
+		WithArgs(warehouse, product, (*uuid.UUID)(nil), int64(-5)).
+		WillReturnError(&pgconn.PgError{Code: "23514"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO stocks").
+		WithArgs(warehouse, product, (*uuid.UUID)(nil), int64(10)).
+		WillReturnRows(mock.NewRows([]string{"quantity"}).AddRow(int64(10)))
+	mock.ExpectQuery("INSERT INTO stock_movements").
+		WithArgs(warehouse, product, (*uuid.UUID)(nil), int64(10), int64(10), "receipt", (*uuid.UUID)(nil), (*uuid.UUID)(nil)).
+		WillReturnRows(mock.NewRows([]string{"id", "created_at"}).AddRow(uuid.Must(uuid.NewV4()), time.Now()))
+	mock.ExpectCommit()
+
+	//run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adjustment, _ := json.Marshal(tt.adjustment)
+			req := httptest.NewRequest(http.MethodPost, "/catalog/stock", bytes.NewBuffer(adjustment))
+			w := httptest.NewRecorder()
+
+			//init router
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.Default()
+			router.POST("/catalog/stock", h.AdjustStock)
+
+			//sent request
+			router.ServeHTTP(w, req)
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, result.StatusCode, tt.want.statusCode)
+		})
+	}
+
+}
+
+func Test_ChangeOrderStatus(t *testing.T) {
+	type want struct {
+		statusCode int
+	}
+	tests := []struct {
+		name   string
+		status string
+		want   want
+	}{
+		{
+			name:   "Bad request",
+			status: "lost",
+			want:   want{statusCode: 400},
+		},
+		{
+			name:   "Paid by admin",
+			status: models.OrderPaid,
+			want:   want{statusCode: 400},
+		},
+		{
+			name:   "Refunded by admin",
+			status: models.OrderRefunded,
+			want:   want{statusCode: 400},
+		},
+		{
+			name:   "Not allowed",
+			status: models.OrderShipped,
+			want:   want{statusCode: 409},
+		},
+		{
+			name:   "Ok",
+			status: models.OrderPacked,
+			want:   want{statusCode: 200},
+		},
+	}
+	//init logger
+	logger := logrus.New()
+
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+
+	//init main components
+	r := repository.NewRepository(mock, logger)
+	s := service.NewService(r, logger)
+	h := NewHandler(s, logger)
+
+	//set mock
+	id := "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"
+	user := "5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10"
+	expectOrder := func(status string) {
+		mock.ExpectQuery("FROM orders").
+			WillReturnRows(mock.NewRows([]string{"id", "user_id", "status", "total", "discount", "created_at", "updated_at"}).
+				AddRow(id, user, status, int64(8990), int64(0), time.Now(), time.Now()))
+		mock.ExpectQuery("FROM order_items").
+			WithArgs([]string{id}).
+			WillReturnRows(mock.NewRows([]string{"order_id", "product_id", "variant_id", "name", "options", "price", "quantity", "weight", "valume"}).
+				AddRow(id, user, "", "milk", map[string]string{}, int64(8990), int64(1), 1.0, 1.0))
+		mock.ExpectQuery("FROM order_histories").
+			WillReturnRows(mock.NewRows([]string{"from_status", "to_status", "user_id", "created_at"}).
+				AddRow("", models.OrderCreated, (*uuid.UUID)(nil), time.Now()))
+	}
+	expectOrder(models.OrderCreated)
+	expectOrder(models.OrderPaid)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE orders").
+		WithArgs(models.OrderPacked, uuid.Must(uuid.FromString(id)), models.OrderPaid).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("INSERT INTO order_histories").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+	expectOrder(models.OrderPacked)
+
+	//run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _ := json.Marshal(models.OrderStatus{Status: tt.status})
+			req := httptest.NewRequest(http.MethodPut, "/orders/"+id+"/status", bytes.NewBuffer(status))
+			w := httptest.NewRecorder()
+
+			//init router
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.Default()
+			router.PUT("/orders/:id/status", h.ChangeOrderStatus)
+
+			//sent request
+			router.ServeHTTP(w, req)
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, result.StatusCode, tt.want.statusCode)
+		})
+	}
+
+}
+
+func Test_PaymentWebhook(t *testing.T) {
+	type want struct {
+		statusCode int
+	}
+	fake, _ := payment.NewFake("secret")
+	intent := "pi_1"
+	event := payment.Event{ID: "evt_1", Type: payment.EventSucceeded, IntentID: intent, Amount: models.NewMoney(8990, models.BaseCurrency)}
+	body, header := fake.Webhook(event, time.Now())
+	//payment of cancelled order is authorized by customer
+	money := models.NewMoney(8990, models.BaseCurrency)
+	cancelled, _ := fake.CreateIntent("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", money)
+	fake.Pay(cancelled.ID)
+	authorized := payment.Event{ID: "evt_2", Type: payment.EventAuthorized, IntentID: cancelled.ID, Amount: money}
+	authorizedBody, authorizedHeader := fake.Webhook(authorized, time.Now())
+	tests := []struct {
+		name   string
+		body   []byte
+		header http.Header
+		want   want
+	}{
+		{
+			name:   "Bad signature",
+			body:   append([]byte(" "), body...),
+			header: header,
+			want:   want{statusCode: 400},
+		},
+		{
+			name:   "Ok",
+			body:   body,
+			header: header,
+			want:   want{statusCode: 200},
+		},
+		{
+			name:   "Repeated event",
+			body:   body,
+			header: header,
+			want:   want{statusCode: 200},
+		},
+		{
+			name:   "Authorized for cancelled order",
+			body:   authorizedBody,
+			header: authorizedHeader,
+			want:   want{statusCode: 200},
+		},
+	}
+	//init logger
+	logger := logrus.New()
+
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+
+	//init main components
+	r := repository.NewRepository(mock, logger)
+	s := service.NewService(r, logger)
+	s.UsePayment(fake)
+	h := NewHandler(s, logger)
+
+	//set mock
+	id := "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"
+	paymentID := "9a1e4c2b-3f5d-4e6a-8b7c-1d2e3f4a5b6c"
+	user := "5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10"
+	payments := func(intent, status string) {
+		mock.ExpectQuery("FROM payments").
+			WithArgs("fake", intent).
+			WillReturnRows(mock.NewRows([]string{"id", "order_id", "provider", "intent_id", "amount", "status", "confirm_url", "created_at"}).
+				AddRow(paymentID, id, "fake", intent, int64(8990), models.PaymentAuthorized, "", time.Now()))
+		mock.ExpectQuery("FROM orders").
+			WillReturnRows(mock.NewRows([]string{"id", "user_id", "status", "total", "discount", "created_at", "updated_at"}).
+				AddRow(id, user, status, int64(8990), int64(0), time.Now(), time.Now()))
+		mock.ExpectQuery("FROM order_items").
+			WillReturnRows(mock.NewRows([]string{"order_id", "product_id", "variant_id", "name", "options", "price", "quantity", "weight", "valume"}))
+		mock.ExpectQuery("FROM order_histories").
+			WillReturnRows(mock.NewRows([]string{"from_status", "to_status", "user_id", "created_at"}))
+	}
+	payments(intent, models.OrderCreated)
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO payment_events").
+		WithArgs("evt_1", "fake", payment.EventSucceeded).
+		WillReturnRows(mock.NewRows([]string{"id"}).AddRow("evt_1"))
+	mock.ExpectExec("UPDATE payments").
+		WithArgs(models.PaymentSucceeded, uuid.Must(uuid.FromString(paymentID)), models.PaymentPrevious(models.PaymentSucceeded)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectQuery("FROM orders").
+		WithArgs(uuid.Must(uuid.FromString(id))).
+		WillReturnRows(mock.NewRows([]string{"status"}).AddRow(models.OrderCreated))
+	mock.ExpectExec("UPDATE orders").
+		WithArgs(models.OrderPaid, uuid.Must(uuid.FromString(id)), models.OrderCreated).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("INSERT INTO order_histories").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+	//repeated event is not claimed and changes nothing
+	payments(intent, models.OrderPaid)
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO payment_events").
+		WithArgs("evt_1", "fake", payment.EventSucceeded).
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectRollback()
+	//payment is released, webhook of provider comes before authorized event is claimed
+	payments(cancelled.ID, models.OrderCancelled)
+	payments(cancelled.ID, models.OrderCancelled)
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO payment_events").
+		WithArgs(pgxmock.AnyArg(), "fake", payment.EventCancelled).
+		WillReturnRows(mock.NewRows([]string{"id"}).AddRow("evt_3"))
+	mock.ExpectExec("UPDATE payments").
+		WithArgs(models.PaymentCancelled, uuid.Must(uuid.FromString(paymentID)), models.PaymentPrevious(models.PaymentCancelled)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO payment_events").
+		WithArgs("evt_2", "fake", payment.EventAuthorized).
+		WillReturnRows(mock.NewRows([]string{"id"}).AddRow("evt_2"))
+	mock.ExpectExec("UPDATE payments").
+		WithArgs(models.PaymentAuthorized, uuid.Must(uuid.FromString(paymentID)), models.PaymentPrevious(models.PaymentAuthorized)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectCommit()
+
+	//run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewBuffer(tt.body))
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+			w := httptest.NewRecorder()
+
+			//init router
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.Default()
+			router.POST("/payments/webhook", h.PaymentWebhook)
+
+			//sent request
+			router.ServeHTTP(w, req)
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, result.StatusCode, tt.want.statusCode)
+		})
+	}
+	assert.Equal(t, mock.ExpectationsWereMet(), nil)
+}
+
+func Test_GetPriceHistory(t *testing.T) {
+	type want struct {
+		statusCode int
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  want
+	}{
+		{
+			name:  "Bad time",
+			query: "?from=yesterday",
+			want:  want{statusCode: 400},
+		},
+		{
+			name:  "Ok",
+			query: "?from=2026-09-01T00:00:00Z&to=2026-10-01T00:00:00Z",
+			want:  want{statusCode: 200},
+		},
+	}
+	//init logger
+	logger := logrus.New()
+
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+
+	//init main components
+	r := repository.NewRepository(mock, logger)
+	s := service.NewService(r, logger)
+	h := NewHandler(s, logger)
+
+	//set mock
+	id := "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"
+	from, _ := time.Parse(time.RFC3339, "2026-09-01T00:00:00Z")
+	to, _ := time.Parse(time.RFC3339, "2026-10-01T00:00:00Z")
+	mock.ExpectQuery("FROM price_histories").
+		WithArgs(uuid.Must(uuid.FromString(id)), from, to).
+		WillReturnRows(mock.NewRows([]string{"id", "old_price", "new_price", "user_id", "schedule_id", "created_at"}).
+			AddRow("9a1e4c2b-3f5d-4e6a-8b7c-1d2e3f4a5b6c", int64(8990), int64(9990), "", "", from.Add(time.Hour)))
+
+	//run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/catalog/product/"+id+"/prices/history"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			//init router
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.Default()
+			router.GET("/catalog/product/:id/prices/history", h.GetPriceHistory)
+
+			//sent request
+			router.ServeHTTP(w, req)
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, result.StatusCode, tt.want.statusCode)
+		})
+	}
+	assert.Equal(t, mock.ExpectationsWereMet(), nil)
+}
+
+func Test_UploadImages(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...)
+	type want struct {
+		statusCode int
+		files      int
+	}
+	tests := []struct {
+		name        string
+		data        []byte
+		second      []byte
+		contentType string
+		chunked     bool
+		want        want
+	}{
+		{
+			name:        "Not image",
+			data:        []byte("<html><body>hello</body></html>"),
+			contentType: "image/png",
+			want:        want{statusCode: 400},
+		},
+		{
+			name:        "Wrong declared type",
+			data:        png,
+			contentType: "image/jpeg",
+			want:        want{statusCode: 400},
+		},
+		{
+			name:        "Too large",
+			data:        append(png, make([]byte, 64)...),
+			contentType: "image/png",
+			want:        want{statusCode: 413},
+		},
+		{
+			name:        "Second not image",
+			data:        png,
+			second:      []byte("<html><body>hello</body></html>"),
+			contentType: "image/png",
+			want:        want{statusCode: 400},
+		},
+		{
+			name:        "Body too large",
+			data:        make([]byte, 2<<20),
+			contentType: "image/png",
+			chunked:     true,
+			want:        want{statusCode: 413},
+		},
+		{
+			name:        "Not attached",
+			data:        png,
+			second:      png,
+			contentType: "image/png",
+			want:        want{statusCode: 500},
+		},
+		{
+			name:        "Ok",
+			data:        png,
+			contentType: "image/png",
+			want:        want{statusCode: 200, files: 1},
+		},
+	}
+	//init logger
+	logger := logrus.New()
+
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+
+	//init main components
+	r := repository.NewRepository(mock, logger)
+	s := service.NewService(r, logger)
+	dir := t.TempDir()
+	s.Storage, _ = storage.NewLocal(dir, "/images")
+	s.ImageSize = 64
+	h := NewHandler(s, logger)
+
+	//set mock
+	id := "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"
+	//second image is not attached, first one is rolled back with it
+	mock.ExpectBegin()
+	mock.ExpectQuery("FOR UPDATE").
+		WillReturnRows(mock.NewRows([]string{"id"}).AddRow(id))
+	mock.ExpectQuery("INSERT INTO product_images").
+		WillReturnRows(mock.NewRows([]string{"id", "position", "is_primary", "status"}).AddRow("5e8a2d1c-7b3f-4a6e-9c8d-2f1e3a4b5c6d", 0, true, models.ImagePending))
+	mock.ExpectQuery("INSERT INTO product_images").
+		WillReturnError(errors.New("connection lost"))
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery("FOR UPDATE").
+		WillReturnRows(mock.NewRows([]string{"id"}).AddRow(id))
+	mock.ExpectQuery("INSERT INTO product_images").
+		WillReturnRows(mock.NewRows([]string{"id", "position", "is_primary", "status"}).AddRow("5e8a2d1c-7b3f-4a6e-9c8d-2f1e3a4b5c6d", 0, true, models.ImagePending))
+	mock.ExpectCommit()
+	mock.ExpectRollback()
+	mock.ExpectQuery("FROM product_images").
+		WillReturnRows(mock.NewRows([]string{"id", "key", "url", "content_type", "size", "position", "is_primary", "status", "sizes", "files"}).
+			AddRow("5e8a2d1c-7b3f-4a6e-9c8d-2f1e3a4b5c6d", "products/a.png", "/images/products/a.png", "image/png", int64(len(png)), 0, true,
+				models.ImagePending, map[string]models.ImageSize{}, []string{}))
+
+	//run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			for i, data := range [][]byte{tt.data, tt.second} {
+				if data == nil {
+					continue
+				}
+				header := textproto.MIMEHeader{}
+				header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="image"; filename="%d.png"`, i))
+				header.Set("Content-Type", tt.contentType)
+				part, _ := form.CreatePart(header)
+				part.Write(data)
+			}
+			form.Close()
+			req := httptest.NewRequest(http.MethodPost, "/catalog/product/"+id+"/images", &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			if tt.chunked {
+				//length is not known before body is read
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+
+			//init router
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.Default()
+			router.POST("/catalog/product/:id/images", h.UploadImages)
+
+			//sent request
+			router.ServeHTTP(w, req)
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, result.StatusCode, tt.want.statusCode)
+			files, _ := filepath.Glob(filepath.Join(dir, "products", "*", "*"))
+			assert.Equal(t, len(files), tt.want.files)
+		})
+	}
+	assert.Equal(t, mock.ExpectationsWereMet(), nil)
+}
+
+func Test_Storefront(t *testing.T) {
+	type want struct {
+		statusCode int
+		cache      string
+	}
+	tests := []struct {
+		name  string
+		path  string
+		found bool
+		etag  bool
+		want  want
+	}{
+		{
+			name:  "Ok",
+			path:  "/store/product/0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21",
+			found: true,
+			want:  want{statusCode: 200, cache: "public, max-age=60"},
+		},
+		{
+			name:  "Not modified",
+			path:  "/store/product/0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21",
+			found: true,
+			etag:  true,
+			want:  want{statusCode: 304, cache: "public, max-age=60"},
+		},
+		{
+			name: "Hidden product",
+			path: "/store/product/5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10",
+			want: want{statusCode: 404, cache: "no-store"},
+		},
+		{
+			name: "Admin route",
+			path: "/catalog/product/0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21",
+			want: want{statusCode: 401},
+		},
+	}
+	//init logger
+	logger := logrus.New()
+
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+
+	//init main components
+	r := repository.NewRepository(mock, logger)
+	s := service.NewService(r, logger)
+	h := NewHandler(s, logger)
+	h.Storefront = Storefront{Enabled: true, MaxAge: time.Minute}
+	gin.SetMode(gin.ReleaseMode)
+	router := h.Init()
+
+	var etag string
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//set mock
+			if tt.found {
+				mock.ExpectQuery("SELECT (.+) FROM products").
+					WithArgs(uuid.FromStringOrNil("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"), false).
+					WillReturnRows(mock.NewRows([]string{"id", "name", "weight", "valume", "description", "photo", "price", "visible", "category", "attributes", "options", "available"}).
+						AddRow("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", "milk", 1.0, 1.0, "", []string{}, int64(8990), true, "food", map[string]interface{}{}, []string{}, int64(5)))
+				mock.ExpectQuery("FROM variants").
+					WithArgs([]string{"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"}, false).
+					WillReturnRows(mock.NewRows([]string{"id", "product_id", "options", "weight", "valume", "price", "barcode", "visible", "available"}))
+				mock.ExpectQuery("FROM product_images").
+					WillReturnRows(mock.NewRows([]string{"product_id", "id", "key", "url", "content_type", "size", "position", "is_primary", "status", "sizes", "files"}))
+				mock.ExpectQuery("FROM promos").
+					WillReturnRows(mock.NewRows([]string{"id", "code", "name", "type", "value", "scope", "targets", "min_total", "starts_at", "ends_at", "usage_limit", "per_user_limit", "used", "stackable", "active"}))
+			} else if tt.want.statusCode == 404 {
+				mock.ExpectQuery("SELECT (.+) FROM products").
+					WillReturnError(pgx.ErrNoRows)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.etag {
+				req.Header.Set("If-None-Match", etag)
+			}
+			w := httptest.NewRecorder()
+
+			//sent request
+			router.ServeHTTP(w, req)
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, result.StatusCode, tt.want.statusCode)
+			assert.Equal(t, result.Header.Get("Cache-Control"), tt.want.cache)
+			if tt.etag {
+				assert.Equal(t, w.Body.Len(), 0)
+			}
+			if tt.found {
+				etag = result.Header.Get("ETag")
+				assert.NotEqual(t, etag, "")
+			}
+			assert.Equal(t, mock.ExpectationsWereMet(), nil)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+//max count of files in one upload
+const maxImages = 10
+
+// @Summary Upload images of product
+// @Security ApiKeyAuth
+// @Tags images
+// @Descriotion upload jpeg, png, gif or webp images to the end of product images, type is checked by content
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "product id"
+// @Param image formData file true "image, up to 10 files"
+// @Param primary formData bool false "first uploaded image becomes primary"
+// @Success 200 {array} models.ImageDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Product not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 413 "Image is too large"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/images [post]
+func (h *Handler) UploadImages(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	maxSize := h.service.MaxImageSize()
+	//files and some place for other fields of form
+	limit := maxSize*maxImages + 1<<20
+	if c.Request.ContentLength > limit {
+		c.Status(http.StatusRequestEntityTooLarge)
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	form, err := c.MultipartForm()
+	if err != nil {
+		h.logger.Error(err)
+		//error of MaxBytesReader has no own type before go 1.19
+		if strings.Contains(err.Error(), "request body too large") {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	files := form.File["image"]
+	if len(files) == 0 || len(files) > maxImages {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	primary := c.PostForm("primary") == "true"
+	uploads := make([]service.ImageUpload, 0, len(files))
+	for _, file := range files {
+		f, err := file.Open()
+		if err != nil {
+			h.logger.Error(err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		//one byte more than allowed is enough to know that file is too large
+		data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+		f.Close()
+		if err != nil {
+			h.logger.Error(err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		uploads = append(uploads, service.ImageUpload{Name: file.Filename, Data: data, Declared: file.Header.Get("Content-Type")})
+	}
+	if err := h.service.UploadImages(id, uploads, primary); err != nil {
+		h.validationError(c, err)
+		return
+	}
+	h.GetImages(c)
+}
+
+// @Summary Show images of product
+// @Security ApiKeyAuth
+// @Tags images
+// @Descriotion View images of product in their order
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Success 200 {array} models.ImageDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/images [get]
+func (h *Handler) GetImages(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	images, err := h.service.Repository.GetImages(id)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, images)
+}
+
+// @Summary Sort images of product
+// @Security ApiKeyAuth
+// @Tags images
+// @Descriotion set order of images, list contains every image of product once
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param input body models.ImageOrder true "ids of images in new order"
+// @Success 200 {array} models.ImageDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Product not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/images [put]
+func (h *Handler) SortImages(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var order models.ImageOrder
+	if err := c.ShouldBindJSON(&order); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	ids := make([]uuid.UUID, len(order.IDs))
+	for i, s := range order.IDs {
+		if ids[i], err = uuid.FromString(s); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+	}
+	if err := h.service.Repository.SortImages(id, ids); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	h.GetImages(c)
+}
+
+// @Summary Set primary image
+// @Security ApiKeyAuth
+// @Tags images
+// @Descriotion make image primary image of product
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param image path string true "image id"
+// @Success 200 {array} models.ImageDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/images/{image}/primary [put]
+func (h *Handler) SetPrimaryImage(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	image, err := uuid.FromString(c.Param("image"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.SetPrimaryImage(id, image); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	h.GetImages(c)
+}
+
+// @Summary Delete image
+// @Security ApiKeyAuth
+// @Tags images
+// @Descriotion delete image of product with its file, next image becomes primary instead of deleted one
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param image path string true "image id"
+// @Success 200 {array} models.ImageDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/images/{image} [delete]
+func (h *Handler) DeleteImage(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	image, err := uuid.FromString(c.Param("image"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.DeleteImage(id, image); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	h.GetImages(c)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/EMus88/Market/internal/service"
+	"github.com/EMus88/Market/internal/spreadsheet"
+
+	"github.com/gin-gonic/gin"
+)
+
+//max size of imported file
+const maxImportSize = 20 << 20
+
+// @Summary Import products
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion create and update products by name from csv or xlsx file in one transaction, columns are fields of product and attributes as "attr:<name>"
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "csv or xlsx file, first row is titles of columns"
+// @Param dry_run formData bool false "only check file"
+// @Param mapping formData string false "fields of columns with other titles, like {"Наименование":"name","Цена":"price"}"
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} models.ImportReport "File has errors"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 413 "File is too large"
+// @Failure 500 "Internal server error"
+// @Router /catalog/import [post]
+func (h *Handler) ImportProducts(c *gin.Context) {
+	if c.Request.ContentLength > maxImportSize {
+		c.Status(http.StatusRequestEntityTooLarge)
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	file, err := c.FormFile("file")
+	if err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	var mapping map[string]string
+	if value := c.PostForm("mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+			h.logger.Error(err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+	}
+	f, err := file.Open()
+	if err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	defer f.Close()
+	rows, err := spreadsheet.Read(f, spreadsheet.FormatOf(file.Filename))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := h.service.ImportProducts(rows, mapping, c.PostForm("dry_run") == "true", userID(c))
+	if err != nil {
+		if errors.Is(err, service.ErrImport) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(errorStatus(err))
+		return
+	}
+	if len(report.Errors) > 0 {
+		c.JSON(http.StatusBadRequest, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// @Summary Checkout
+// @Security ApiKeyAuth
+// @Tags orders
+// @Descriotion make order from cart with names and prices at purchase time, cart is cleared
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.OrderDTO
+// @Failure 400 "Cart is empty"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {object} models.Cart "Cart has warnings, stock is over or cart was changed"
+// @Failure 500 "Internal server error"
+// @Router /orders [post]
+func (h *Handler) Checkout(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	order, cart, err := h.service.Checkout(*user)
+	if err != nil {
+		if errors.Is(err, service.ErrCartInvalid) {
+			c.JSON(http.StatusConflict, cart)
+			return
+		}
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Show my orders
+// @Security ApiKeyAuth
+// @Tags orders
+// @Descriotion View orders of user from newest
+// @Accept json
+// @Produce json
+// @Param status query string false "Status of order" Enums(created, paid, packed, shipped, delivered, cancelled, refunded)
+// @Param limit query int false "Orders on page, 20 by default"
+// @Param cursor query string false "Next cursor from previous page"
+// @Success 200 {object} models.OrdersPage
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 500 "Internal server error"
+// @Router /orders [get]
+func (h *Handler) GetOrders(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	h.getOrders(c, user)
+}
+
+// @Summary Show my order
+// @Security ApiKeyAuth
+// @Tags orders
+// @Descriotion View order of user with items and history of statuses
+// @Accept json
+// @Produce json
+// @Param id path string true "order id"
+// @Success 200 {object} models.OrderDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal server error"
+// @Router /orders/{id} [get]
+func (h *Handler) GetOrder(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	h.getOrder(c, user)
+}
+
+// @Summary Cancel my order
+// @Security ApiKeyAuth
+// @Tags orders
+// @Descriotion cancel not paid order of user, stock is returned
+// @Accept json
+// @Produce json
+// @Param id path string true "order id"
+// @Success 200 {object} models.OrderDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Order can't be cancelled"
+// @Failure 500 "Internal server error"
+// @Router /orders/{id}/cancel [post]
+func (h *Handler) CancelOrder(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	order, err := h.service.ChangeOrderStatus(id, models.OrderCancelled, user, user)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Show all orders
+// @Security ApiKeyAuth
+// @Tags orders
+// @Descriotion View orders of all users from newest
+// @Accept json
+// @Produce json
+// @Param status query string false "Status of order" Enums(created, paid, packed, shipped, delivered, cancelled, refunded)
+// @Param user_id query string false "User id"
+// @Param limit query int false "Orders on page, 20 by default"
+// @Param cursor query string false "Next cursor from previous page"
+// @Success 200 {object} models.OrdersPage
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /orders/all [get]
+func (h *Handler) GetAllOrders(c *gin.Context) {
+	h.getOrders(c, nil)
+}
+
+// @Summary Show any order
+// @Security ApiKeyAuth
+// @Tags orders
+// @Descriotion View order of any user with items and history of statuses
+// @Accept json
+// @Produce json
+// @Param id path string true "order id"
+// @Success 200 {object} models.OrderDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /orders/all/{id} [get]
+func (h *Handler) GetAnyOrder(c *gin.Context) {
+	h.getOrder(c, nil)
+}
+
+// @Summary Change order status
+// @Security ApiKeyAuth
+// @Tags orders
+// @Descriotion move order to next status: paid -> packed -> shipped -> delivered, created -> cancelled; paid and refunded are set by payment only
+// @Accept json
+// @Produce json
+// @Param id path string true "order id"
+// @Param input body models.OrderStatus true "new status"
+// @Success 200 {object} models.OrderDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Transition is not allowed"
+// @Failure 500 "Internal server error"
+// @Router /orders/{id}/status [put]
+func (h *Handler) ChangeOrderStatus(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var status models.OrderStatus
+	if err := c.ShouldBindJSON(&status); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	order, err := h.service.ChangeOrderStatus(id, status.Status, nil, userID(c))
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+//orders of user or all orders for administrator
+func (h *Handler) getOrders(c *gin.Context, user *uuid.UUID) {
+	var filter models.OrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	orders, err := h.service.Repository.GetOrders(&filter, user)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, orders)
+}
+
+func (h *Handler) getOrder(c *gin.Context, user *uuid.UUID) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	order, err := h.service.Repository.GetOrder(id, user)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/EMus88/Market/internal/payment"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// @Summary Pay my order
+// @Security ApiKeyAuth
+// @Tags payments
+// @Descriotion start payment of created order in provider, customer confirms it by confirm url
+// @Accept json
+// @Produce json
+// @Param id path string true "order id"
+// @Success 200 {object} models.PaymentDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Order can't be paid"
+// @Failure 500 "Internal server error"
+// @Router /orders/{id}/pay [post]
+func (h *Handler) PayOrder(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	p, err := h.service.PayOrder(id, *user)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// @Summary Refund order
+// @Security ApiKeyAuth
+// @Tags payments
+// @Descriotion return money of paid order, order becomes refunded when provider confirms refund
+// @Accept json
+// @Produce json
+// @Param id path string true "order id"
+// @Success 200 {object} models.PaymentDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Order can't be refunded"
+// @Failure 500 "Internal server error"
+// @Router /orders/{id}/refund [post]
+func (h *Handler) RefundOrder(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	p, err := h.service.RefundOrder(id)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// @Summary Payment webhook
+// @Tags payments
+// @Descriotion signed event of payment provider, repeated events are ignored
+// @Accept json
+// @Produce json
+// @Success 200 "Event is processed"
+// @Failure 400 "Not valid signature or event"
+// @Failure 409 "Order can't be changed"
+// @Failure 500 "Internal server error"
+// @Router /payments/webhook [post]
+func (h *Handler) PaymentWebhook(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.HandleWebhook(body, c.Request.Header); err != nil {
+		h.logger.Error(err)
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Confirm fake payment
+// @Tags payments
+// @Descriotion sandbox page of fake provider, customer confirms or declines payment
+// @Accept json
+// @Produce json
+// @Param intent path string true "intent id"
+// @Param decline query bool false "Decline payment"
+// @Success 200 "Payment is confirmed"
+// @Failure 404 "Not found"
+// @Failure 409 "Payment is finished already"
+// @Router /payments/fake/{intent}/pay [post]
+func (h *Handler) FakePay(c *gin.Context) {
+	fake, ok := h.service.Payment.(*payment.Fake)
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	var err error
+	if c.Query("decline") == "true" {
+		err = fake.Fail(c.Param("intent"))
+	} else {
+		err = fake.Pay(c.Param("intent"))
+	}
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
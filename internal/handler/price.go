@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// @Summary Show price history
+// @Security ApiKeyAuth
+// @Tags prices
+// @Descriotion View changes of product price from newest with their authors
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param from query string false "From time, RFC3339"
+// @Param to query string false "To time, RFC3339"
+// @Param limit query int false "Records count, 20 by default"
+// @Success 200 {array} models.PriceChange
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/prices/history [get]
+func (h *Handler) GetPriceHistory(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	var filter models.PriceHistoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	changes, err := h.service.Repository.GetPriceHistory(id, &filter)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
+
+// @Summary Show price at time
+// @Security ApiKeyAuth
+// @Tags prices
+// @Descriotion View price of product at moment in past
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param at query string true "Time, RFC3339"
+// @Success 200 {object} models.PriceAt
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/prices/at [get]
+func (h *Handler) GetPriceAt(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	at, err := time.Parse(time.RFC3339, c.Query("at"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	price, err := h.service.Repository.GetPriceAt(id, at)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, price)
+}
+
+// @Summary Show scheduled prices
+// @Security ApiKeyAuth
+// @Tags prices
+// @Descriotion View waiting and applied future prices of product
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Success 200 {array} models.ScheduledPriceDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/prices/scheduled [get]
+func (h *Handler) GetScheduledPrices(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	prices, err := h.service.Repository.GetScheduledPrices(id)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, prices)
+}
+
+// @Summary Schedule price
+// @Security ApiKeyAuth
+// @Tags prices
+// @Descriotion set price of product which becomes effective at start time
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param input body models.ScheduledPriceDTO true "price and start time"
+// @Success 200 {object} models.ScheduledPriceDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/prices/scheduled [post]
+func (h *Handler) AddScheduledPrice(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var price models.ScheduledPriceDTO
+	if err := c.ShouldBindJSON(&price); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := service.ValidateSchedule(&price, time.Now()); err != nil {
+		h.validationError(c, err)
+		return
+	}
+	if err := h.service.Repository.AddScheduledPrice(id, &price, userID(c)); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, price)
+}
+
+// @Summary Cancel scheduled price
+// @Security ApiKeyAuth
+// @Tags prices
+// @Descriotion delete price which is not applied yet
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param schedule path string true "scheduled price id"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found or applied already"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/prices/scheduled/{schedule} [delete]
+func (h *Handler) DeleteScheduledPrice(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	schedule, err := uuid.FromString(c.Param("schedule"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.DeleteScheduledPrice(id, schedule); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// @Summary Show promos
+// @Security ApiKeyAuth
+// @Tags promos
+// @Descriotion View all promo codes and automatic discounts from newest
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.PromoDTO
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /promos [get]
+func (h *Handler) GetPromos(c *gin.Context) {
+	promos, err := h.service.Repository.GetPromos()
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, promos)
+}
+
+// @Summary Show promo
+// @Security ApiKeyAuth
+// @Tags promos
+// @Descriotion View promo with count of uses
+// @Accept json
+// @Produce json
+// @Param id path string true "promo id"
+// @Success 200 {object} models.PromoDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /promos/{id} [get]
+func (h *Handler) GetPromo(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	promo, err := h.service.Repository.GetPromo(id)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, promo)
+}
+
+// @Summary Add promo
+// @Security ApiKeyAuth
+// @Tags promos
+// @Descriotion add promo code or automatic discount (without code) for cart, categories or products
+// @Accept json
+// @Produce json
+// @Param input body models.PromoDTO true "rules of promo"
+// @Success 200 {object} models.PromoDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 "Code already exist"
+// @Failure 500 "Internal server error"
+// @Router /promos [post]
+func (h *Handler) AddPromo(c *gin.Context) {
+	//bindig request
+	var promo models.PromoDTO
+	if err := c.ShouldBindJSON(&promo); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := service.ValidatePromo(&promo); err != nil {
+		h.validationError(c, err)
+		return
+	}
+	if err := h.service.Repository.AddPromo(&promo); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, promo)
+}
+
+// @Summary Update promo
+// @Security ApiKeyAuth
+// @Tags promos
+// @Descriotion replace rules of promo, count of uses is kept
+// @Accept json
+// @Produce json
+// @Param id path string true "promo id"
+// @Param input body models.PromoDTO true "rules of promo"
+// @Success 200 {object} models.PromoDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Code already exist"
+// @Failure 500 "Internal server error"
+// @Router /promos/{id} [put]
+func (h *Handler) UpdatePromo(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var promo models.PromoDTO
+	if err := c.ShouldBindJSON(&promo); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := service.ValidatePromo(&promo); err != nil {
+		h.validationError(c, err)
+		return
+	}
+	if err := h.service.Repository.UpdatePromo(id, &promo); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	h.GetPromo(c)
+}
+
+// @Summary Delete promo
+// @Security ApiKeyAuth
+// @Tags promos
+// @Descriotion delete promo, orders keep their discounts
+// @Accept json
+// @Produce json
+// @Param id path string true "promo id"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /promos/{id} [delete]
+func (h *Handler) DeletePromo(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.DeletePromo(id); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Enter promo code
+// @Security ApiKeyAuth
+// @Tags cart
+// @Descriotion apply promo code to cart, previous code is replaced
+// @Accept json
+// @Produce json
+// @Param input body models.PromoCode true "promo code"
+// @Success 200 {object} models.Cart
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Code not found or can't be used"
+// @Failure 500 "Internal server error"
+// @Router /cart/promo [post]
+func (h *Handler) SetCartPromo(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	//bindig request
+	var promo models.PromoCode
+	if err := c.ShouldBindJSON(&promo); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	code := strings.ToUpper(strings.TrimSpace(promo.Code))
+	if err := h.service.Repository.SetCartPromo(*user, code); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	h.GetCart(c)
+}
+
+// @Summary Remove promo code
+// @Security ApiKeyAuth
+// @Tags cart
+// @Descriotion remove promo code from cart
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Cart
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 500 "Internal server error"
+// @Router /cart/promo [delete]
+func (h *Handler) DeleteCartPromo(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	if err := h.service.Repository.DeleteCartPromo(*user); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	h.GetCart(c)
+}
+
+//discounted prices by automatic promos, they are set before conversion to currency
+func (h *Handler) discountPrices(c *gin.Context, products []models.ProductDTO) bool {
+	if err := h.service.DiscountPrices(products); err != nil {
+		c.Status(errorStatus(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// @Summary Shipping quotes for cart
+// @Security ApiKeyAuth
+// @Tags shipping
+// @Descriotion prices of delivery of cart to region by tariffs of its zone, weight is max of real and volumetric weight
+// @Accept json
+// @Produce json
+// @Param region query string false "Region of delivery"
+// @Success 200 {object} models.ShippingQuotes
+// @Failure 400 "Cart is empty or no zone for region"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 500 "Internal server error"
+// @Router /cart/shipping [get]
+func (h *Handler) QuoteCart(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	var filter models.ShippingFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	quotes, err := h.service.QuoteCart(*user, filter.Region)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, quotes)
+}
+
+// @Summary Shipping quotes for my order
+// @Security ApiKeyAuth
+// @Tags shipping
+// @Descriotion prices of delivery of order to region by sizes of goods at purchase time
+// @Accept json
+// @Produce json
+// @Param id path string true "order id"
+// @Param region query string false "Region of delivery"
+// @Success 200 {object} models.ShippingQuotes
+// @Failure 400 "Bad request or no zone for region"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal server error"
+// @Router /orders/{id}/shipping [get]
+func (h *Handler) QuoteOrder(c *gin.Context) {
+	user := userID(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	var filter models.ShippingFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	quotes, err := h.service.QuoteOrder(id, user, filter.Region)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, quotes)
+}
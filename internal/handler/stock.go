@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// @Summary Show warehouses
+// @Security ApiKeyAuth
+// @Tags stock
+// @Descriotion View all warehouses
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Warehouse
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/warehouses [get]
+func (h *Handler) GetWarehouses(c *gin.Context) {
+	warehouses, err := h.service.Repository.GetWarehouses()
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, warehouses)
+}
+
+// @Summary Add warehouse
+// @Security ApiKeyAuth
+// @Tags stock
+// @Descriotion add new warehouse
+// @Accept json
+// @Produce json
+// @Param input body models.Warehouse true "warehouse info"
+// @Success 200 {object} models.Warehouse
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/warehouses [post]
+func (h *Handler) AddWarehouse(c *gin.Context) {
+	//bindig request
+	var warehouse models.Warehouse
+	if err := c.ShouldBindJSON(&warehouse); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.AddWarehouse(&warehouse); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, warehouse)
+}
+
+// @Summary Adjust stock
+// @Security ApiKeyAuth
+// @Tags stock
+// @Descriotion change quantity of product or its variant in warehouse, change is written to stock ledger
+// @Accept json
+// @Produce json
+// @Param input body models.StockAdjustment true "positive delta for receipt, negative for write-off"
+// @Success 200 {object} models.StockMovement
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Not enough stock"
+// @Failure 500 "Internal server error"
+// @Router /catalog/stock [post]
+func (h *Handler) AdjustStock(c *gin.Context) {
+	//bindig request
+	var adjustment models.StockAdjustment
+	if err := c.ShouldBindJSON(&adjustment); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	movement, err := h.service.Repository.AdjustStock(&adjustment, userID(c))
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, movement)
+}
+
+// @Summary Show stock movements
+// @Security ApiKeyAuth
+// @Tags stock
+// @Descriotion View last records of stock ledger
+// @Accept json
+// @Produce json
+// @Param product_id query string false "Product id"
+// @Param warehouse_id query string false "Warehouse id"
+// @Param limit query int false "Records count, 20 by default"
+// @Success 200 {array} models.StockMovement
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/stock/movements [get]
+func (h *Handler) GetMovements(c *gin.Context) {
+	var filter models.MovementFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	movements, err := h.service.Repository.GetMovements(&filter)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, movements)
+}
+
+// @Summary Show stock of product
+// @Security ApiKeyAuth
+// @Tags stock
+// @Descriotion View quantity of product and its variants in each warehouse
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Success 200 {array} models.Stock
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 {string} json "{"error":"credential error"}"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/stock [get]
+func (h *Handler) GetStock(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	stocks, err := h.service.Repository.GetStock(id)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, stocks)
+}
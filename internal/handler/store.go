@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+//anonymous read api of visible catalog for visitors and search engines
+type Storefront struct {
+	Enabled bool
+	//time for which responses are cached by browsers and proxies
+	MaxAge time.Duration
+}
+
+// @Summary Show storefront catalog
+// @Tags store
+// @Descriotion View visible products page by page without authorization
+// @Produce json
+// @Param category query string false "Category with subcategories"
+// @Param min_price query string false "Min price in RUB, for example 99.90"
+// @Param max_price query string false "Max price in RUB, for example 99.90"
+// @Param min_weight query number false "Min weight"
+// @Param max_weight query number false "Max weight"
+// @Param min_valume query number false "Min valume"
+// @Param max_valume query number false "Max valume"
+// @Param only_in_stock query bool false "Only products in stock"
+// @Param attr query string false "Attribute filter as attr[name]=value or attr[name]=min..max"
+// @Param sort query string false "Sort order" Enums(name, price, price_desc, newest)
+// @Param limit query int false "Products on page, 20 by default"
+// @Param cursor query string false "Next cursor from previous page"
+// @Param currency query string false "Currency of prices, RUB by default"
+// @Success 200 {object} models.CatalogPage
+// @Success 304 "Not modified"
+// @Failure 400 "Bad request"
+// @Failure 500 "Internal server error"
+// @Router /store/catalog [get]
+func (h *Handler) StoreCatalog(c *gin.Context) {
+	h.GetCatalog(c)
+}
+
+// @Summary Search in storefront
+// @Tags store
+// @Descriotion Search visible products by name and description without authorization
+// @Produce json
+// @Param category query string false "Category"
+// @Param product query string true "Product"
+// @Param min_price query string false "Min price in RUB, for example 99.90"
+// @Param max_price query string false "Max price in RUB, for example 99.90"
+// @Param min_weight query number false "Min weight"
+// @Param max_weight query number false "Max weight"
+// @Param min_valume query number false "Min valume"
+// @Param max_valume query number false "Max valume"
+// @Param only_in_stock query bool false "Only products in stock"
+// @Param attr query string false "Attribute filter as attr[name]=value or attr[name]=min..max"
+// @Param currency query string false "Currency of prices, RUB by default"
+// @Success 200 {object} models.SearchResult
+// @Success 304 "Not modified"
+// @Failure 400 "Bad request"
+// @Failure 500 "Internal server error"
+// @Router /store/search [get]
+func (h *Handler) StoreSearch(c *gin.Context) {
+	h.Search(c)
+}
+
+// @Summary Storefront autocomplete
+// @Tags store
+// @Descriotion Names of products and categories which start with typed text
+// @Produce json
+// @Param q query string true "Typed text"
+// @Success 200 {object} models.Suggestions
+// @Success 304 "Not modified"
+// @Failure 400 "Bad request"
+// @Failure 500 "Internal server error"
+// @Router /store/suggest [get]
+func (h *Handler) StoreSuggest(c *gin.Context) {
+	h.Suggest(c)
+}
+
+// @Summary Storefront categories
+// @Tags store
+// @Descriotion Tree of visible categories without authorization
+// @Produce json
+// @Success 200 {array} models.CategoryNode
+// @Success 304 "Not modified"
+// @Failure 500 "Internal server error"
+// @Router /store/tree [get]
+func (h *Handler) StoreTree(c *gin.Context) {
+	h.GetCategoryTree(c)
+}
+
+// @Summary Storefront product
+// @Tags store
+// @Descriotion get visible product by id with visible variants without authorization
+// @Produce json
+// @Param id path string true "product id"
+// @Param currency query string false "Currency of prices, RUB by default"
+// @Success 200 {object} models.ProductDTO
+// @Success 304 "Not modified"
+// @Failure 400 "Bad request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal server error"
+// @Router /store/product/{id} [get]
+func (h *Handler) StoreProduct(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	product, err := h.service.Repository.GetProduct(id, false)
+	if err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	products := []models.ProductDTO{*product}
+	if !h.discountPrices(c, products) || !h.convertPrices(c, products) {
+		return
+	}
+	c.JSON(http.StatusOK, products[0])
+}
+
+//response is kept until handler ends to set its ETag
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+//successful responses are cached by clients and proxies for max age, after it they are checked by ETag
+//and not changed response is 304 without body
+func (h *Handler) StoreCache(c *gin.Context) {
+	w := &bufferedWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+	c.Writer = w.ResponseWriter
+
+	if w.Status() != http.StatusOK {
+		c.Header("Cache-Control", "no-store")
+		if w.body.Len() == 0 {
+			c.Writer.WriteHeaderNow()
+			return
+		}
+		c.Writer.Write(w.body.Bytes())
+		return
+	}
+	sum := sha256.Sum256(w.body.Bytes())
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.Storefront.MaxAge.Seconds())))
+	if match(c.GetHeader("If-None-Match"), etag) {
+		c.Writer.WriteHeader(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Writer.Write(w.body.Bytes())
+}
+
+//If-None-Match has list of etags or "*"
+func match(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// @Summary Add variant of product
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion add sku with value of each option of product
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param input body models.VariantDTO true "variant info"
+// @Success 200 {object} models.VariantDTO
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Variant with same options or barcode already exist"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/variants [post]
+func (h *Handler) AddVariant(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var variant models.VariantDTO
+	if err := c.ShouldBindJSON(&variant); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if !validPrice(variant.Price) {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.CheckVariant(id, variant.Options); err != nil {
+		h.validationError(c, err)
+		return
+	}
+	if variant.Visible == nil {
+		visible := true
+		variant.Visible = &visible
+	}
+	//Round float to 2 decimal places
+	variant.Weight = math.Round(variant.Weight*100) / 100
+	variant.Valume = math.Round(variant.Valume*100) / 100
+	if err := h.service.Repository.AddVariant(id, &variant); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.JSON(http.StatusOK, variant)
+}
+
+// @Summary Update variant of product
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion update some fields of variant
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param variant path string true "variant id"
+// @Param input body models.VariantUpdate true "changed fields"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 409 "Variant with same options or barcode already exist"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/variants/{variant} [patch]
+func (h *Handler) UpdateVariant(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	variantID, err := uuid.FromString(c.Param("variant"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	//bindig request
+	var variant models.VariantUpdate
+	if err := c.ShouldBindJSON(&variant); err != nil {
+		h.logger.Error(err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if variant.Price != nil && !validPrice(*variant.Price) {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if variant.Options != nil {
+		if err := h.service.CheckVariant(id, variant.Options); err != nil {
+			h.validationError(c, err)
+			return
+		}
+	}
+	//Round float to 2 decimal places
+	if variant.Weight != nil {
+		*variant.Weight = math.Round(*variant.Weight*100) / 100
+	}
+	if variant.Valume != nil {
+		*variant.Valume = math.Round(*variant.Valume*100) / 100
+	}
+	if err := h.service.Repository.UpdateVariant(id, variantID, &variant); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Delete variant of product
+// @Security ApiKeyAuth
+// @Tags catalog
+// @Descriotion delete variant by id
+// @Accept json
+// @Produce json
+// @Param id path string true "product id"
+// @Param variant path string true "variant id"
+// @Success 200 "Ok"
+// @Failure 400 "Bad request"
+// @Failure 401 {string} json "{"error":"unauthenticated"}"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal server error"
+// @Router /catalog/product/{id}/variants/{variant} [delete]
+func (h *Handler) DeleteVariant(c *gin.Context) {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	variantID, err := uuid.FromString(c.Param("variant"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Repository.DeleteVariant(id, variantID); err != nil {
+		c.Status(errorStatus(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
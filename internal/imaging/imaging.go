@@ -0,0 +1,185 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/spf13/viper"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+//formats of generated images
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+)
+
+const jpegQuality = 85
+
+//decoded image takes 4 bytes per pixel, so bigger images are not decoded if limit is not set in config
+const defaultMaxPixels = 40000000
+
+var extensions = map[string]string{
+	FormatJPEG: ".jpg",
+	FormatPNG:  ".png",
+	FormatWebP: ".webp",
+}
+
+var (
+	ErrDecode = errors.New("error: image can't be decoded")
+	ErrConfig = errors.New("error: not valid image sizes")
+	ErrPixels = errors.New("error: image has too many pixels")
+)
+
+//sizes which are generated if they are not set in config
+var defaultSizes = []Size{
+	{Name: "thumb", Width: 160},
+	{Name: "medium", Width: 480},
+	{Name: "large", Width: 1200},
+}
+
+//image sizes from config
+type Config struct {
+	Sizes     []Size `mapstructure:"sizes"`
+	WebP      bool   `mapstructure:"webp"`
+	MaxPixels int    `mapstructure:"max_pixels"`
+}
+
+//max width of image, smaller images are not enlarged
+type Size struct {
+	Name  string `mapstructure:"name"`
+	Width int    `mapstructure:"width"`
+}
+
+//generated image of one size
+type Rendition struct {
+	Size        string
+	Format      string
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+//makes images of all sizes from source image
+type Pipeline struct {
+	sizes     []Size
+	webp      bool
+	maxPixels int
+}
+
+//pipeline from "images" section of config
+func Load() (*Pipeline, error) {
+	cfg := Config{WebP: true}
+	if err := viper.UnmarshalKey("images", &cfg); err != nil {
+		return nil, err
+	}
+	return New(cfg)
+}
+
+func New(cfg Config) (*Pipeline, error) {
+	sizes := cfg.Sizes
+	if len(sizes) == 0 {
+		sizes = defaultSizes
+	}
+	names := make(map[string]bool, len(sizes))
+	for _, size := range sizes {
+		if size.Name == "" || size.Width <= 0 || names[size.Name] {
+			return nil, fmt.Errorf("%w: size %q", ErrConfig, size.Name)
+		}
+		names[size.Name] = true
+	}
+	maxPixels := cfg.MaxPixels
+	if maxPixels <= 0 {
+		maxPixels = defaultMaxPixels
+	}
+	return &Pipeline{
+		sizes:     sizes,
+		webp:      cfg.WebP,
+		maxPixels: maxPixels,
+	}, nil
+}
+
+//images of every size in format of source (jpeg stays jpeg, others become png) and in webp
+func (p *Pipeline) Process(data []byte) ([]Rendition, error) {
+	//small file can have huge dimensions, so they are checked before decoding
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > int64(p.maxPixels) {
+		return nil, fmt.Errorf("%w: %dx%d, max is %d pixels", ErrPixels, cfg.Width, cfg.Height, p.maxPixels)
+	}
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	formats := []string{FormatPNG}
+	if format == FormatJPEG {
+		formats[0] = FormatJPEG
+	}
+	if p.webp {
+		formats = append(formats, FormatWebP)
+	}
+	var renditions []Rendition
+	for _, size := range p.sizes {
+		img := Resize(src, size.Width)
+		for _, f := range formats {
+			var buf bytes.Buffer
+			if err := Encode(&buf, img, f); err != nil {
+				//webp has limit of side, such size is served only in main format
+				if errors.Is(err, ErrWebPSize) {
+					continue
+				}
+				return nil, err
+			}
+			renditions = append(renditions, Rendition{
+				Size:        size.Name,
+				Format:      f,
+				ContentType: "image/" + f,
+				Ext:         extensions[f],
+				Width:       img.Bounds().Dx(),
+				Height:      img.Bounds().Dy(),
+				Data:        buf.Bytes(),
+			})
+		}
+	}
+	return renditions, nil
+}
+
+//scale image down to width with same proportions
+func Resize(src image.Image, width int) image.Image {
+	b := src.Bounds()
+	if b.Dx() <= width {
+		return src
+	}
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Rect, src, b, draw.Src, nil)
+	return dst
+}
+
+func Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case FormatJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case FormatPNG:
+		return png.Encode(w, img)
+	case FormatWebP:
+		return EncodeWebP(w, img)
+	default:
+		return fmt.Errorf("%w: format %s", ErrConfig, format)
+	}
+}
@@ -0,0 +1,115 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/go-playground/assert"
+	"golang.org/x/image/webp"
+)
+
+//gradient with noise and transparent corner, so all kinds of prefix codes are used
+func testImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	seed := uint32(7)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			seed = seed*1103515245 + 12345
+			a := uint8(0xff)
+			if x < width/4 && y < height/4 {
+				a = uint8(x * 255 / width)
+			}
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 255 / width), G: uint8(y*255/height) ^ uint8(seed>>28), B: uint8(seed >> 24), A: a})
+		}
+	}
+	return img
+}
+
+func Test_EncodeWebP(t *testing.T) {
+	tests := []struct {
+		name string
+		img  *image.NRGBA
+	}{
+		{name: "One pixel", img: testImage(1, 1)},
+		{name: "One color", img: image.NewNRGBA(image.Rect(0, 0, 40, 30))},
+		{name: "Two colors", img: func() *image.NRGBA {
+			img := image.NewNRGBA(image.Rect(0, 0, 9, 9))
+			for i := 0; i < 9; i++ {
+				img.SetNRGBA(i, i, color.NRGBA{R: 200, G: 10, B: 10, A: 255})
+			}
+			return img
+		}()},
+		{name: "Noise", img: testImage(600, 530)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.Equal(t, EncodeWebP(&buf, tt.img), nil)
+			decoded, err := webp.Decode(&buf)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, decoded.Bounds(), tt.img.Rect)
+			//lossless: every pixel is the same
+			same := true
+			for y := 0; y < tt.img.Rect.Dy() && same; y++ {
+				for x := 0; x < tt.img.Rect.Dx() && same; x++ {
+					same = color.NRGBAModel.Convert(decoded.At(x, y)) == tt.img.NRGBAAt(x, y)
+				}
+			}
+			assert.Equal(t, same, true)
+		})
+	}
+}
+
+func Test_Process(t *testing.T) {
+	var source bytes.Buffer
+	jpeg.Encode(&source, testImage(800, 400), nil)
+	p, err := New(Config{Sizes: []Size{{Name: "thumb", Width: 100}, {Name: "large", Width: 1000}}, WebP: true})
+	assert.Equal(t, err, nil)
+
+	renditions, err := p.Process(source.Bytes())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(renditions), 4)
+	for _, r := range renditions {
+		img, format, err := image.Decode(bytes.NewReader(r.Data))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, format, r.Format)
+		assert.Equal(t, img.Bounds().Dx(), r.Width)
+	}
+	//jpeg stays jpeg, large image is not enlarged
+	assert.Equal(t, renditions[0].Format, FormatJPEG)
+	assert.Equal(t, renditions[0].Width, 100)
+	assert.Equal(t, renditions[0].Height, 50)
+	assert.Equal(t, renditions[1].Format, FormatWebP)
+	assert.Equal(t, renditions[2].Width, 800)
+
+	//other formats become png
+	source.Reset()
+	png.Encode(&source, testImage(50, 50))
+	renditions, err = p.Process(source.Bytes())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, renditions[0].Format, FormatPNG)
+
+	_, err = p.Process([]byte("not image"))
+	assert.NotEqual(t, err, nil)
+	//image taller than webp allows has no webp
+	source.Reset()
+	png.Encode(&source, image.NewNRGBA(image.Rect(0, 0, 2, maxWebPSide+1)))
+	renditions, err = p.Process(source.Bytes())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(renditions), 2)
+	assert.Equal(t, renditions[0].Format, FormatPNG)
+	assert.Equal(t, renditions[1].Format, FormatPNG)
+	//image over pixel limit is not decoded
+	small, err := New(Config{MaxPixels: 2000})
+	assert.Equal(t, err, nil)
+	_, err = small.Process(source.Bytes())
+	assert.Equal(t, errors.Is(err, ErrPixels), true)
+
+	_, err = New(Config{Sizes: []Size{{Name: "thumb", Width: 100}, {Name: "thumb", Width: 200}}})
+	assert.NotEqual(t, err, nil)
+}
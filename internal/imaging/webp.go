@@ -0,0 +1,399 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"io"
+	"sort"
+)
+
+//lossless webp (VP8L) without backward references: pixels are coded by prefix codes
+//after subtract green and gradient predictor transforms
+
+const (
+	vp8lSignature = 0x2f
+	//max width and height of webp image
+	maxWebPSide = 1 << 14
+
+	transformPredictor     = 0
+	transformSubtractGreen = 2
+	//clamp(left + top - top left) for every channel
+	predictorGradient = 12
+	//predictor mode is set for blocks of 512x512 pixels
+	predictorBits = 9
+
+	//alphabets of prefix codes: green with length prefixes, red, blue, alpha and distance
+	greenAlphabet    = 256 + 24
+	literalAlphabet  = 256
+	distanceAlphabet = 40
+
+	maxCodeLength       = 15
+	maxLengthCodeLength = 7
+)
+
+var ErrWebPSize = errors.New("error: image is too large for webp")
+
+//order of code lengths of code length code
+var lengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+//write image as lossless webp
+func EncodeWebP(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > maxWebPSide || height > maxWebPSide {
+		return ErrWebPSize
+	}
+	nrgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(nrgba, nrgba.Rect, img, b.Min, draw.Src)
+
+	//argb pixels with green subtracted from red and blue
+	pixels := make([]uint32, width*height)
+	alpha := false
+	for i := range pixels {
+		p := nrgba.Pix[i*4 : i*4+4]
+		r, g, b, a := uint32(p[0]), uint32(p[1]), uint32(p[2]), uint32(p[3])
+		alpha = alpha || a != 0xff
+		pixels[i] = a<<24 | ((r-g)&0xff)<<16 | g<<8 | (b-g)&0xff
+	}
+	residuals := predict(pixels, width, height)
+
+	var bw bitWriter
+	bw.write(vp8lSignature, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if alpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	//version
+	bw.write(0, 3)
+
+	//transforms are inverted by decoder in reverse order
+	bw.write(1, 1)
+	bw.write(transformSubtractGreen, 2)
+	bw.write(1, 1)
+	bw.write(transformPredictor, 2)
+	bw.write(predictorBits-2, 3)
+	//image of predictor modes, all blocks have one mode so its pixels take no bits
+	bw.write(0, 1)
+	bw.writeSimpleCode(predictorGradient)
+	for i := 0; i < 4; i++ {
+		bw.writeSimpleCode(0)
+	}
+	bw.write(0, 1)
+
+	//main image without color cache and with one group of prefix codes
+	bw.write(0, 1)
+	bw.write(0, 1)
+	histograms := [5][]uint32{
+		make([]uint32, greenAlphabet),
+		make([]uint32, literalAlphabet),
+		make([]uint32, literalAlphabet),
+		make([]uint32, literalAlphabet),
+		make([]uint32, distanceAlphabet),
+	}
+	for _, p := range residuals {
+		histograms[0][p>>8&0xff]++
+		histograms[1][p>>16&0xff]++
+		histograms[2][p&0xff]++
+		histograms[3][p>>24]++
+	}
+	//distance code is never used, but it must be written
+	histograms[4][0] = 1
+	var codes [5][]uint16
+	var lengths [5][]uint8
+	for i, histogram := range histograms {
+		lengths[i], codes[i] = bw.writeCode(histogram)
+	}
+	for _, p := range residuals {
+		for i, symbol := range [4]uint32{p >> 8 & 0xff, p >> 16 & 0xff, p & 0xff, p >> 24} {
+			bw.write(uint32(codes[i][symbol]), uint(lengths[i][symbol]))
+		}
+	}
+	data := bw.bytes()
+
+	//riff container with one VP8L chunk, chunks are padded to even size
+	size := len(data) + len(data)%2
+	header := make([]byte, 20)
+	copy(header, "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+size))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+	if len(data)%2 == 1 {
+		data = append(data, 0)
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+//residuals of gradient predictor, first pixel is predicted by black, first row by left and first column by top pixel
+func predict(pixels []uint32, width, height int) []uint32 {
+	residuals := make([]uint32, len(pixels))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			var prediction uint32
+			switch {
+			case x == 0 && y == 0:
+				prediction = 0xff000000
+			case y == 0:
+				prediction = pixels[i-1]
+			case x == 0:
+				prediction = pixels[i-width]
+			default:
+				prediction = gradient(pixels[i-1], pixels[i-width], pixels[i-width-1])
+			}
+			residuals[i] = subPixels(pixels[i], prediction)
+		}
+	}
+	return residuals
+}
+
+func gradient(left, top, topLeft uint32) uint32 {
+	var p uint32
+	for shift := uint(0); shift < 32; shift += 8 {
+		v := int(left>>shift&0xff) + int(top>>shift&0xff) - int(topLeft>>shift&0xff)
+		if v < 0 {
+			v = 0
+		} else if v > 0xff {
+			v = 0xff
+		}
+		p |= uint32(v) << shift
+	}
+	return p
+}
+
+//difference of every channel modulo 256
+func subPixels(a, b uint32) uint32 {
+	var p uint32
+	for shift := uint(0); shift < 32; shift += 8 {
+		p |= ((a>>shift&0xff - b>>shift&0xff) & 0xff) << shift
+	}
+	return p
+}
+
+//bits are written from least significant one
+type bitWriter struct {
+	buf  []byte
+	acc  uint64
+	used uint
+}
+
+func (bw *bitWriter) write(v uint32, n uint) {
+	bw.acc |= uint64(v) << bw.used
+	bw.used += n
+	for bw.used >= 8 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc >>= 8
+		bw.used -= 8
+	}
+}
+
+func (bw *bitWriter) bytes() []byte {
+	if bw.used > 0 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc, bw.used = 0, 0
+	}
+	return bw.buf
+}
+
+//prefix code of one symbol, the symbol takes no bits
+func (bw *bitWriter) writeSimpleCode(symbol uint32) {
+	bw.write(1, 1)
+	bw.write(0, 1)
+	if symbol < 2 {
+		bw.write(0, 1)
+		bw.write(symbol, 1)
+		return
+	}
+	bw.write(1, 1)
+	bw.write(symbol, 8)
+}
+
+//write prefix code of histogram and return lengths and bit reversed codes of its symbols
+func (bw *bitWriter) writeCode(histogram []uint32) ([]uint8, []uint16) {
+	var used []uint32
+	for symbol, count := range histogram {
+		if count > 0 {
+			used = append(used, uint32(symbol))
+		}
+	}
+	lengths := make([]uint8, len(histogram))
+	//one or two symbols below 256 are written as simple code
+	if len(used) <= 2 && used[len(used)-1] < 256 {
+		if len(used) == 1 {
+			bw.writeSimpleCode(used[0])
+			return lengths, make([]uint16, len(histogram))
+		}
+		bw.write(1, 1)
+		bw.write(1, 1)
+		bw.write(1, 1)
+		bw.write(used[0], 8)
+		bw.write(used[1], 8)
+		lengths[used[0]], lengths[used[1]] = 1, 1
+		return lengths, canonicalCodes(lengths)
+	}
+	lengths = codeLengths(histogram, maxCodeLength)
+
+	//code lengths are written by code length code, runs of zeros are written by symbols 17 and 18
+	type token struct {
+		symbol    int
+		extra     uint32
+		extraBits uint
+	}
+	var tokens []token
+	lengthHistogram := make([]uint32, len(lengthCodeOrder))
+	for i := 0; i < len(lengths); {
+		if lengths[i] != 0 {
+			tokens = append(tokens, token{symbol: int(lengths[i])})
+			i++
+			continue
+		}
+		run := 1
+		for i+run < len(lengths) && lengths[i+run] == 0 && run < 138 {
+			run++
+		}
+		switch {
+		case run < 3:
+			for j := 0; j < run; j++ {
+				tokens = append(tokens, token{symbol: 0})
+			}
+		case run <= 10:
+			tokens = append(tokens, token{symbol: 17, extra: uint32(run - 3), extraBits: 3})
+		default:
+			tokens = append(tokens, token{symbol: 18, extra: uint32(run - 11), extraBits: 7})
+		}
+		i += run
+	}
+	for _, t := range tokens {
+		lengthHistogram[t.symbol]++
+	}
+	lengthLengths := codeLengths(lengthHistogram, maxLengthCodeLength)
+	lengthCodes := canonicalCodes(lengthLengths)
+
+	count := len(lengthCodeOrder)
+	for count > 4 && lengthLengths[lengthCodeOrder[count-1]] == 0 {
+		count--
+	}
+	bw.write(0, 1)
+	bw.write(uint32(count-4), 4)
+	for _, symbol := range lengthCodeOrder[:count] {
+		bw.write(uint32(lengthLengths[symbol]), 3)
+	}
+	//lengths of all symbols of alphabet are written
+	bw.write(0, 1)
+	for _, t := range tokens {
+		bw.write(uint32(lengthCodes[t.symbol]), uint(lengthLengths[t.symbol]))
+		bw.write(t.extra, t.extraBits)
+	}
+	return lengths, canonicalCodes(lengths)
+}
+
+//lengths of huffman codes not longer than limit, counts are raised until the tree fits into limit
+func codeLengths(histogram []uint32, limit int) []uint8 {
+	type node struct {
+		count       uint64
+		symbol      int
+		left, right int
+	}
+	lengths := make([]uint8, len(histogram))
+	for countMin := uint64(1); ; countMin *= 2 {
+		var nodes []node
+		for symbol, count := range histogram {
+			if count == 0 {
+				continue
+			}
+			c := uint64(count)
+			if c < countMin {
+				c = countMin
+			}
+			nodes = append(nodes, node{count: c, symbol: symbol, left: -1, right: -1})
+		}
+		//code needs two symbols, unused symbol takes second code
+		if len(nodes) == 1 {
+			other := 0
+			if nodes[0].symbol == 0 {
+				other = 1
+			}
+			lengths[nodes[0].symbol], lengths[other] = 1, 1
+			return lengths
+		}
+		sort.Slice(nodes, func(i, j int) bool {
+			if nodes[i].count != nodes[j].count {
+				return nodes[i].count < nodes[j].count
+			}
+			return nodes[i].symbol < nodes[j].symbol
+		})
+		//two queues: sorted leaves and inner nodes which are created in order of their counts
+		leaves := len(nodes)
+		next, inner := 0, leaves
+		pick := func() int {
+			if next < leaves && (inner >= len(nodes) || nodes[next].count <= nodes[inner].count) {
+				next++
+				return next - 1
+			}
+			inner++
+			return inner - 1
+		}
+		for len(nodes) < 2*leaves-1 {
+			a, b := pick(), pick()
+			nodes = append(nodes, node{count: nodes[a].count + nodes[b].count, symbol: -1, left: a, right: b})
+		}
+		//depth of leaves from root
+		depth := make([]int, len(nodes))
+		tooLong := false
+		for i := len(nodes) - 1; i >= leaves; i-- {
+			depth[nodes[i].left] = depth[i] + 1
+			depth[nodes[i].right] = depth[i] + 1
+		}
+		for i := 0; i < leaves; i++ {
+			if depth[i] > limit {
+				tooLong = true
+				break
+			}
+			lengths[nodes[i].symbol] = uint8(depth[i])
+		}
+		if !tooLong {
+			return lengths
+		}
+		for i := range lengths {
+			lengths[i] = 0
+		}
+	}
+}
+
+//canonical codes of lengths, bits of every code are reversed because codes are read from its first bit
+func canonicalCodes(lengths []uint8) []uint16 {
+	var count [maxCodeLength + 1]int
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+		}
+	}
+	var next [maxCodeLength + 1]int
+	code := 0
+	for bits := 1; bits <= maxCodeLength; bits++ {
+		code = (code + count[bits-1]) << 1
+		next[bits] = code
+	}
+	codes := make([]uint16, len(lengths))
+	for symbol, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		c := next[l]
+		next[l]++
+		var reversed int
+		for i := 0; i < int(l); i++ {
+			reversed = reversed<<1 | c>>i&1
+		}
+		codes[symbol] = uint16(reversed)
+	}
+	return codes
+}
@@ -0,0 +1,21 @@
+package models
+
+import uuid "github.com/gofrs/uuid"
+
+//attribute of products in category and its subcategories
+type CategoryAttribute struct {
+	ID         uuid.UUID `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()" json:"id"`
+	CategoryID uuid.UUID `gorm:"type:uuid; not null; uniqueIndex:category_attribute" json:"category_id"`
+	Name       string    `gorm:"type:varchar(100); not null; uniqueIndex:category_attribute" json:"name" binding:"required"`
+	Type       string    `gorm:"type:varchar(10); not null" json:"type" binding:"required,oneof=number string bool"`
+	Unit       string    `gorm:"type:varchar(20)" json:"unit,omitempty"`
+	Required   bool      `json:"required"`
+}
+
+//filter by attribute value or by range of number attribute
+type AttributeFilter struct {
+	Name  string
+	Value *string
+	Min   *float64
+	Max   *float64
+}
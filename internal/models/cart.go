@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+//warnings of cart line found on reading of cart
+const (
+	WarningHidden       = "hidden"
+	WarningOutOfStock   = "out_of_stock"
+	WarningNotEnough    = "not_enough_stock"
+	WarningPriceChanged = "price_changed"
+)
+
+//product in cart of user, price is remembered to warn about its change
+type CartItem struct {
+	ID        uuid.UUID  `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()"`
+	UserID    uuid.UUID  `gorm:"type:uuid; not null; index"`
+	ProductID uuid.UUID  `gorm:"type:uuid; not null"`
+	VariantID *uuid.UUID `gorm:"type:uuid"`
+	Quantity  int64      `gorm:"not null; check:quantity > 0"`
+	Price     int64      `gorm:"not null"`
+	CreatedAt time.Time  `gorm:"default:now()"`
+}
+
+type CartItemDTO struct {
+	ProductID uuid.UUID  `json:"product_id" binding:"required"`
+	VariantID *uuid.UUID `json:"variant_id,omitempty"`
+	Quantity  int64      `json:"quantity" binding:"required,min=1"`
+}
+
+type CartQuantity struct {
+	Quantity int64 `json:"quantity" binding:"required,min=1"`
+}
+
+type CartLine struct {
+	ID        string            `json:"id"`
+	ProductID string            `json:"product_id"`
+	VariantID string            `json:"variant_id,omitempty"`
+	Name      string            `json:"name"`
+	Options   map[string]string `json:"options,omitempty"`
+	Price     Money             `json:"price"`
+	Quantity  int64             `json:"quantity"`
+	Sum       Money             `json:"sum"`
+	Discount  Money             `json:"discount"`
+	Available int64             `json:"available_quantity"`
+	Weight    float64           `json:"weight"`
+	Valume    float64           `json:"valume"`
+	Warnings  []string          `json:"warnings,omitempty"`
+	//state of product on reading, it is checked by service
+	Visible    bool   `json:"-"`
+	AddedPrice Money  `json:"-"`
+	Category   string `json:"-"`
+}
+
+type Cart struct {
+	Lines []CartLine `json:"lines"`
+	//sum of lines which can be bought
+	Subtotal  Money             `json:"subtotal"`
+	Discount  Money             `json:"discount"`
+	Discounts []AppliedDiscount `json:"discounts,omitempty"`
+	PromoCode string            `json:"promo_code,omitempty"`
+	//subtotal with discount
+	Total Money `json:"total"`
+	//all lines can be bought
+	Valid bool `json:"valid"`
+}
@@ -3,6 +3,18 @@ package models
 import uuid "github.com/gofrs/uuid"
 
 type Category struct {
-	ID   uuid.UUID `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()" json:"id,omitempty" `
-	Name string    `gorm:"index:indx_category;type:varchar(150);column:category; not null; unique" json:"name" binding:"required"`
+	ID       uuid.UUID  `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()" json:"id,omitempty" `
+	Name     string     `gorm:"index:indx_category;type:varchar(150);column:category; not null; unique" json:"name" binding:"required"`
+	Visible  bool       `gorm:"default:true" json:"visible"`
+	ParentID *uuid.UUID `gorm:"type:uuid; index" json:"parent_id,omitempty"`
+}
+
+type CategoryNode struct {
+	ID       uuid.UUID       `json:"id"`
+	Name     string          `json:"name"`
+	Children []*CategoryNode `json:"children,omitempty"`
+}
+
+type CategoryMove struct {
+	ParentID *uuid.UUID `json:"parent_id"`
 }
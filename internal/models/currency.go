@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+//explicit price of product in not base currency
+type ProductPrice struct {
+	ProductID uuid.UUID `gorm:"primary_key; type:uuid"`
+	Currency  string    `gorm:"primary_key; type:varchar(3)"`
+	Amount    int64     `gorm:"not null"`
+}
+
+//units of currency for one unit of base currency
+type ExchangeRate struct {
+	Currency  string    `gorm:"primary_key; type:varchar(3)" json:"currency" binding:"required"`
+	Rate      string    `gorm:"type:numeric(18,8); not null" json:"rate" binding:"required" example:"5.25"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+//uploaded image of product, file is kept in storage by key, its sizes are generated in background
+type ProductImage struct {
+	ID          uuid.UUID `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()"`
+	ProductID   uuid.UUID `gorm:"type:uuid; not null; index"`
+	Key         string    `gorm:"type:varchar(255); not null; unique"`
+	URL         string    `gorm:"type:text; not null"`
+	ContentType string    `gorm:"type:varchar(50); not null"`
+	Size        int64     `gorm:"not null"`
+	Position    int       `gorm:"not null; default:0"`
+	IsPrimary   bool      `gorm:"not null; default:false"`
+	Status      string    `gorm:"type:varchar(20); not null; default:'pending'; index"`
+	Sizes       []byte    `gorm:"type:jsonb; not null; default:'{}'"`
+	Files       []string  `gorm:"type:text[]"`
+	ProcessedAt *time.Time
+	CreatedAt   time.Time `gorm:"default:now()"`
+}
+
+type ImageDTO struct {
+	ID          string               `json:"id"`
+	Key         string               `json:"-"`
+	URL         string               `json:"url"`
+	ContentType string               `json:"content_type"`
+	Size        int64                `json:"size"`
+	Position    int                  `json:"position"`
+	Primary     bool                 `json:"primary"`
+	Status      string               `json:"status"`
+	Sizes       map[string]ImageSize `json:"sizes,omitempty"`
+	Files       []string             `json:"-"`
+}
+
+//generated image of one size in format of source and in webp
+type ImageSize struct {
+	URL    string `json:"url"`
+	WebP   string `json:"webp,omitempty"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+//statuses of generating of image sizes, files of sizes are kept for deleting
+const (
+	ImagePending    = "pending"
+	ImageProcessing = "processing"
+	ImageReady      = "ready"
+	ImageFailed     = "failed"
+)
+
+//all images of product in new order
+type ImageOrder struct {
+	IDs []string `json:"ids" binding:"required"`
+}
@@ -0,0 +1,23 @@
+package models
+
+//product from row of imported file
+type ImportRow struct {
+	Row     int
+	Product ProductDTO
+}
+
+//reason why row of file can't be imported
+type ImportError struct {
+	Row    int    `json:"row"`
+	Column string `json:"column,omitempty"`
+	Error  string `json:"error"`
+}
+
+//result of import, nothing is written when file has errors or it is dry run
+type ImportReport struct {
+	DryRun  bool          `json:"dry_run"`
+	Rows    int           `json:"rows"`
+	Created int           `json:"created"`
+	Updated int           `json:"updated"`
+	Errors  []ImportError `json:"errors,omitempty"`
+}
@@ -0,0 +1,213 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+//currency of prices stored in catalog
+const BaseCurrency = "RUB"
+
+//number of digits after point for ISO 4217 currencies
+var currencyExponents = map[string]int{
+	"RUB": 2,
+	"KZT": 2,
+	"BYN": 2,
+	"USD": 2,
+	"EUR": 2,
+}
+
+var (
+	ErrCurrency = errors.New("error: unknown currency")
+	ErrAmount   = errors.New("error: not valid amount")
+	ErrRate     = errors.New("error: not valid exchange rate")
+)
+
+//exact amount of money in minor units (kopecks) of currency
+type Money struct {
+	Amount   int64  `json:"amount" swaggertype:"string" example:"89.90"`
+	Currency string `json:"currency" example:"RUB"`
+}
+
+//money json has amount as string to keep it exact
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func IsCurrency(currency string) bool {
+	_, ok := currencyExponents[currency]
+	return ok
+}
+
+//digits of exchange rate as it is stored in db: numeric(18,8)
+const (
+	rateWhole    = 10
+	rateFraction = 8
+)
+
+//parse positive exchange rate like "5.25", only plain decimal is accepted
+func ParseRate(s string) (*big.Rat, error) {
+	s = strings.TrimSpace(s)
+	whole, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, fraction = s[:i], s[i+1:]
+	}
+	if whole == "" || len(whole) > rateWhole || len(fraction) > rateFraction || !isDigits(whole) || !isDigits(fraction) {
+		return nil, ErrRate
+	}
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok || rate.Sign() <= 0 {
+		return nil, ErrRate
+	}
+	return rate, nil
+}
+
+//convert money to currency by rate (units of currency for one unit of m.Currency),
+//result is rounded to minor unit of currency half away from zero
+func (m Money) Convert(rate *big.Rat, currency string) (Money, error) {
+	from, ok := currencyExponents[m.Currency]
+	if !ok {
+		return Money{}, ErrCurrency
+	}
+	to, ok := currencyExponents[currency]
+	if !ok {
+		return Money{}, ErrCurrency
+	}
+	//amount * rate * 10^to / 10^from
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), rate)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(to-from))), nil)
+	if to > from {
+		value.Mul(value, new(big.Rat).SetInt(scale))
+	} else {
+		value.Quo(value, new(big.Rat).SetInt(scale))
+	}
+	amount := roundHalfUp(value)
+	if !amount.IsInt64() {
+		return Money{}, ErrAmount
+	}
+	return Money{Amount: amount.Int64(), Currency: currency}, nil
+}
+
+//money multiplied by part/whole in currency, rounded as on conversion,
+//used to keep share of discount in price set in other currency
+func (m Money) Share(part, whole int64, currency string) (Money, error) {
+	if whole == 0 {
+		return Money{}, ErrAmount
+	}
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), big.NewRat(part, whole))
+	amount := roundHalfUp(value)
+	if !amount.IsInt64() {
+		return Money{}, ErrAmount
+	}
+	return Money{Amount: amount.Int64(), Currency: currency}, nil
+}
+
+//round half away from zero
+func roundHalfUp(r *big.Rat) *big.Int {
+	num := new(big.Int).Abs(r.Num())
+	quo, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return quo
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+//parse decimal string like "89.90" without float rounding
+func ParseMoney(s string, currency string) (Money, error) {
+	exp, ok := currencyExponents[currency]
+	if !ok {
+		return Money{}, ErrCurrency
+	}
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	whole, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, fraction = s[:i], s[i+1:]
+	}
+	if whole == "" || len(fraction) > exp || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, ErrAmount
+	}
+	fraction += strings.Repeat("0", exp-len(fraction))
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, ErrAmount
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+//decimal string of amount without currency
+func (m Money) String() string {
+	exp := currencyExponents[m.Currency]
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	if exp == 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
+	}
+	s := fmt.Sprintf("%0*d", exp+1, amount)
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.String(), m.Currency})
+}
+
+//accept {"amount":"89.90","currency":"RUB"} or plain "89.90" in base currency,
+//amount may be json number, it is parsed from its text without float
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var v moneyJSON
+	if len(b) > 0 && b[0] == '{' {
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+	} else {
+		v.Amount = b
+	}
+	if v.Currency == "" {
+		v.Currency = BaseCurrency
+	}
+	amount := strings.Trim(string(v.Amount), `"`)
+	parsed, err := ParseMoney(amount, v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
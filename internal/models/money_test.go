@@ -0,0 +1,124 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-playground/assert"
+)
+
+func Test_ParseMoney(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		amount int64
+		err    error
+	}{
+		{name: "Whole", in: "89", amount: 8900},
+		{name: "Kopecks", in: "89.90", amount: 8990},
+		{name: "One digit", in: "0.1", amount: 10},
+		{name: "Negative", in: "-1.05", amount: -105},
+		{name: "Too precise", in: "1.005", err: ErrAmount},
+		{name: "Not number", in: "1e3", err: ErrAmount},
+		{name: "Empty", in: "", err: ErrAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseMoney(tt.in, "RUB")
+			assert.Equal(t, err, tt.err)
+			assert.Equal(t, m.Amount, tt.amount)
+		})
+	}
+}
+
+func Test_MoneyJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{name: "Object", in: `{"amount":"89.90","currency":"KZT"}`, out: `{"amount":"89.90","currency":"KZT"}`},
+		{name: "Number", in: `{"amount":0.3}`, out: `{"amount":"0.30","currency":"RUB"}`},
+		{name: "String", in: `"1000000.01"`, out: `{"amount":"1000000.01","currency":"RUB"}`},
+		{name: "Negative", in: `"-0.05"`, out: `{"amount":"-0.05","currency":"RUB"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			assert.Equal(t, json.Unmarshal([]byte(tt.in), &m), nil)
+			out, err := json.Marshal(m)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, string(out), tt.out)
+		})
+	}
+}
+
+func Test_ParseRate(t *testing.T) {
+	tests := []struct {
+		rate string
+		ok   bool
+	}{
+		{rate: "5.25", ok: true},
+		{rate: " 0.00000001 ", ok: true},
+		{rate: "1234567890.5", ok: true},
+		{rate: "0"},
+		{rate: "1/3"},
+		{rate: "1e400"},
+		{rate: "-1"},
+		{rate: ".5"},
+		{rate: "0.000000001"},
+		{rate: "12345678901"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rate, func(t *testing.T) {
+			_, err := ParseRate(tt.rate)
+			assert.Equal(t, err == nil, tt.ok)
+		})
+	}
+}
+
+func Test_Convert(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		rate   string
+		want   int64
+	}{
+		{name: "Exact", amount: 10000, rate: "5.2", want: 52000},
+		{name: "Half up", amount: 1, rate: "0.5", want: 1},
+		{name: "Below half", amount: 1, rate: "0.49999", want: 0},
+		{name: "Negative half", amount: -1, rate: "0.5", want: -1},
+		{name: "Small rate", amount: 8990, rate: "0.0345", want: 310},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := ParseRate(tt.rate)
+			assert.Equal(t, err, nil)
+			m, err := NewMoney(tt.amount, "RUB").Convert(rate, "BYN")
+			assert.Equal(t, err, nil)
+			assert.Equal(t, m, NewMoney(tt.want, "BYN"))
+		})
+	}
+}
+
+func Test_Share(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		part   int64
+		whole  int64
+		want   int64
+	}{
+		{name: "Exact", amount: 8000, part: 1000, whole: 10000, want: 800},
+		{name: "Rounded", amount: 8990, part: 1150, whole: 9990, want: 1035},
+		{name: "Half up", amount: 1, part: 1, whole: 2, want: 1},
+		{name: "Big amount", amount: 1 << 40, part: 1 << 30, whole: 1 << 30, want: 1 << 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMoney(tt.amount, "RUB").Share(tt.part, tt.whole, "BYN")
+			assert.Equal(t, err, nil)
+			assert.Equal(t, m, NewMoney(tt.want, "BYN"))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+//statuses of order
+const (
+	OrderCreated   = "created"
+	OrderPaid      = "paid"
+	OrderPacked    = "packed"
+	OrderShipped   = "shipped"
+	OrderDelivered = "delivered"
+	OrderCancelled = "cancelled"
+	OrderRefunded  = "refunded"
+)
+
+//allowed next statuses of order, not paid order is cancelled and paid one is refunded
+var orderTransitions = map[string][]string{
+	OrderCreated:   {OrderPaid, OrderCancelled},
+	OrderPaid:      {OrderPacked, OrderRefunded},
+	OrderPacked:    {OrderShipped, OrderRefunded},
+	OrderShipped:   {OrderDelivered, OrderRefunded},
+	OrderDelivered: {OrderRefunded},
+}
+
+func CanTransition(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type Order struct {
+	ID        uuid.UUID `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()"`
+	UserID    uuid.UUID `gorm:"type:uuid; not null; index"`
+	Status    string    `gorm:"type:varchar(20); not null; index"`
+	Total     int64     `gorm:"not null"`
+	Discount  int64     `gorm:"not null; default:0"`
+	CreatedAt time.Time `gorm:"default:now(); index"`
+	UpdatedAt time.Time `gorm:"default:now()"`
+}
+
+//product of order with name and price at purchase time
+type OrderItem struct {
+	ID        uuid.UUID  `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()"`
+	OrderID   uuid.UUID  `gorm:"type:uuid; not null; index"`
+	ProductID uuid.UUID  `gorm:"type:uuid; not null"`
+	VariantID *uuid.UUID `gorm:"type:uuid"`
+	Name      string     `gorm:"type:varchar(150); not null"`
+	Options   []byte     `gorm:"type:jsonb; not null; default:'{}'"`
+	Price     int64      `gorm:"not null"`
+	Quantity  int64      `gorm:"not null"`
+	Weight    float64    `gorm:"not null; default:0"`
+	Valume    float64    `gorm:"not null; default:0"`
+}
+
+//every change of order status
+type OrderHistory struct {
+	ID        uuid.UUID  `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()" json:"-"`
+	OrderID   uuid.UUID  `gorm:"type:uuid; not null; index" json:"-"`
+	From      string     `gorm:"type:varchar(20); column:from_status" json:"from,omitempty"`
+	To        string     `gorm:"type:varchar(20); not null; column:to_status" json:"to"`
+	UserID    *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	CreatedAt time.Time  `gorm:"default:now()" json:"created_at"`
+}
+
+type OrderItemDTO struct {
+	ProductID string            `json:"product_id"`
+	VariantID string            `json:"variant_id,omitempty"`
+	Name      string            `json:"name"`
+	Options   map[string]string `json:"options,omitempty"`
+	Price     Money             `json:"price"`
+	Quantity  int64             `json:"quantity"`
+	Weight    float64           `json:"weight"`
+	Valume    float64           `json:"valume"`
+}
+
+type OrderDTO struct {
+	ID        string         `json:"id"`
+	UserID    string         `json:"user_id"`
+	Status    string         `json:"status"`
+	Items     []OrderItemDTO `json:"items,omitempty"`
+	Discount  Money          `json:"discount"`
+	Total     Money          `json:"total"`
+	History   []OrderHistory `json:"history,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+//paid and refunded are set only by payment webhook and refund of order
+type OrderStatus struct {
+	Status string `json:"status" binding:"required,oneof=packed shipped delivered cancelled"`
+}
+
+type OrderFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=created paid packed shipped delivered cancelled refunded"`
+	UserID string `form:"user_id" binding:"omitempty,uuid"`
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+}
+
+type OrdersPage struct {
+	Orders     []OrderDTO `json:"orders"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert"
+)
+
+func Test_CanTransition(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		ok   bool
+	}{
+		{from: OrderCreated, to: OrderPaid, ok: true},
+		{from: OrderCreated, to: OrderCancelled, ok: true},
+		{from: OrderPaid, to: OrderPacked, ok: true},
+		{from: OrderPacked, to: OrderShipped, ok: true},
+		{from: OrderShipped, to: OrderDelivered, ok: true},
+		{from: OrderDelivered, to: OrderRefunded, ok: true},
+		{from: OrderCreated, to: OrderShipped},
+		{from: OrderPaid, to: OrderCancelled},
+		{from: OrderCancelled, to: OrderPaid},
+		{from: OrderRefunded, to: OrderCreated},
+		{from: OrderPaid, to: OrderPaid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+"-"+tt.to, func(t *testing.T) {
+			assert.Equal(t, CanTransition(tt.from, tt.to), tt.ok)
+		})
+	}
+}
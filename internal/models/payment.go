@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+//statuses of payment
+const (
+	PaymentPending    = "pending"
+	PaymentAuthorized = "authorized"
+	PaymentSucceeded  = "succeeded"
+	PaymentFailed     = "failed"
+	PaymentRefunded   = "refunded"
+	PaymentCancelled  = "cancelled"
+)
+
+//statuses from which payment can come to status, so late event doesn't return payment back
+var paymentTransitions = map[string][]string{
+	PaymentAuthorized: {PaymentPending},
+	PaymentSucceeded:  {PaymentPending, PaymentAuthorized},
+	PaymentFailed:     {PaymentPending, PaymentAuthorized},
+	PaymentCancelled:  {PaymentPending, PaymentAuthorized},
+	PaymentRefunded:   {PaymentPending, PaymentAuthorized, PaymentSucceeded},
+}
+
+func PaymentPrevious(status string) []string {
+	return paymentTransitions[status]
+}
+
+//payment of order in provider, failed payment can be repeated by new one
+type Payment struct {
+	ID         uuid.UUID `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()"`
+	OrderID    uuid.UUID `gorm:"type:uuid; not null; index"`
+	Provider   string    `gorm:"type:varchar(50); not null; uniqueIndex:payments_intent"`
+	IntentID   string    `gorm:"type:varchar(255); not null; uniqueIndex:payments_intent"`
+	Amount     int64     `gorm:"not null"`
+	Status     string    `gorm:"type:varchar(20); not null"`
+	ConfirmURL string    `gorm:"type:varchar(255)"`
+	CreatedAt  time.Time `gorm:"default:now()"`
+	UpdatedAt  time.Time `gorm:"default:now()"`
+}
+
+//processed webhook event, repeated event is skipped
+type PaymentEvent struct {
+	ID        string    `gorm:"primary_key; type:varchar(255)"`
+	Provider  string    `gorm:"primary_key; type:varchar(50)"`
+	Type      string    `gorm:"type:varchar(50); not null"`
+	CreatedAt time.Time `gorm:"default:now()"`
+}
+
+type PaymentDTO struct {
+	ID         string    `json:"id"`
+	OrderID    string    `json:"order_id"`
+	Provider   string    `json:"provider"`
+	IntentID   string    `json:"intent_id"`
+	Amount     Money     `json:"amount"`
+	Status     string    `json:"status"`
+	ConfirmURL string    `json:"confirm_url,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+//changes of payment and its order by webhook event, they are written with event in one transaction
+type PaymentChange struct {
+	Event     PaymentEvent
+	PaymentID uuid.UUID
+	Status    string
+	OrderID   uuid.UUID
+	//status of order after event, empty status doesn't change order
+	OrderStatus string
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+//change of product price, records are never changed
+type PriceHistory struct {
+	ID         uuid.UUID  `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()"`
+	ProductID  uuid.UUID  `gorm:"type:uuid; not null; index"`
+	OldPrice   int64      `gorm:"not null"`
+	NewPrice   int64      `gorm:"not null"`
+	UserID     *uuid.UUID `gorm:"type:uuid"`
+	ScheduleID *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt  time.Time  `gorm:"default:now(); index"`
+}
+
+//price which becomes price of product at start time
+type ScheduledPrice struct {
+	ID        uuid.UUID  `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()"`
+	ProductID uuid.UUID  `gorm:"type:uuid; not null; index"`
+	Price     int64      `gorm:"not null"`
+	StartsAt  time.Time  `gorm:"not null; index"`
+	UserID    *uuid.UUID `gorm:"type:uuid"`
+	AppliedAt *time.Time
+	CreatedAt time.Time `gorm:"default:now()"`
+}
+
+type PriceChange struct {
+	ID       string `json:"id"`
+	OldPrice Money  `json:"old_price"`
+	NewPrice Money  `json:"new_price"`
+	//author of change, schedule is set when change was scheduled
+	UserID     string    `json:"user_id,omitempty"`
+	ScheduleID string    `json:"schedule_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ScheduledPriceDTO struct {
+	ID        string     `json:"id,omitempty"`
+	Price     Money      `json:"price" binding:"required"`
+	StartsAt  time.Time  `json:"starts_at" binding:"required"`
+	UserID    string     `json:"user_id,omitempty"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type PriceHistoryFilter struct {
+	From  *time.Time `form:"from"`
+	To    *time.Time `form:"to"`
+	Limit int        `form:"limit"`
+}
+
+//price of product at moment
+type PriceAt struct {
+	Price Money     `json:"price"`
+	At    time.Time `json:"at"`
+}
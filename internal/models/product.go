@@ -1,6 +1,10 @@
 package models
 
-import uuid "github.com/gofrs/uuid"
+import (
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+)
 
 type Product struct {
 	ID          uuid.UUID `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()"`
@@ -9,23 +13,131 @@ type Product struct {
 	Valume      float64   `gorm:"not null"`
 	Description string    `gorm:"type:varchar(255)"`
 	Photo       []string  `gorm:"type:text[]"`
-	Price       uint64    `gorm:"not null"`
+	Price       int64     `gorm:"not null"`
 	Visible     bool      `gorm:"default:true"`
 	CategoryID  uuid.UUID `gorm:"type:uuid; not null"`
+	CreatedAt   time.Time `gorm:"default:now(); index"`
+	Attributes  []byte    `gorm:"type:jsonb; not null; default:'{}'"`
+	Options     []string  `gorm:"type:text[]"`
 }
 
 type ProductDTO struct {
-	Name        string   `json:"name" binding:"required" valid:"alpha"`
-	Weight      float64  `json:"weight" binding:"required"`
-	Valume      float64  `json:"valume" binding:"required"`
-	Description string   `json:"description,omitempty" `
-	Photo       []string `json:"photo,omitempty"`
-	Price       float64  `json:"price" binding:"required"`
-	Visible     bool     `json:"visible,omitempty"`
-	Category    string   `json:"category"`
+	ID          string                 `json:"id,omitempty"`
+	Name        string                 `json:"name" binding:"required" valid:"alpha"`
+	Weight      float64                `json:"weight" binding:"required"`
+	Valume      float64                `json:"valume" binding:"required"`
+	Description string                 `json:"description,omitempty" `
+	Photo       []string               `json:"photo,omitempty"`
+	Images      []ImageDTO             `json:"images,omitempty"`
+	Price       Money                  `json:"price" binding:"required"`
+	Discount    *Money                 `json:"discount_price,omitempty"`
+	Visible     bool                   `json:"visible,omitempty"`
+	Category    string                 `json:"category"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	Options     []string               `json:"options,omitempty"`
+	Variants    []VariantDTO           `json:"variants,omitempty"`
+	InStock     bool                   `json:"in_stock"`
+	Available   int64                  `json:"available_quantity"`
+	Highlight   string                 `json:"highlight,omitempty"`
 }
 
 type Visible struct {
 	Name    string `json:"name" binding:"required"`
 	Visible bool   `json:"visible"`
 }
+
+type ProductUpdate struct {
+	Name        *string                `json:"name,omitempty"`
+	Weight      *float64               `json:"weight,omitempty"`
+	Valume      *float64               `json:"valume,omitempty"`
+	Description *string                `json:"description,omitempty"`
+	Photo       *[]string              `json:"photo,omitempty"`
+	Price       *Money                 `json:"price,omitempty"`
+	Visible     *bool                  `json:"visible,omitempty"`
+	Category    *string                `json:"category,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	Options     *[]string              `json:"options,omitempty"`
+}
+
+type ProductFilter struct {
+	Category   string            `form:"category"`
+	MinPrice   *Money            `form:"min_price"`
+	MaxPrice   *Money            `form:"max_price"`
+	MinWeight  *float64          `form:"min_weight"`
+	MaxWeight  *float64          `form:"max_weight"`
+	MinValume  *float64          `form:"min_valume"`
+	MaxValume  *float64          `form:"max_valume"`
+	InStock    bool              `form:"only_in_stock"`
+	Attributes []AttributeFilter `form:"-"`
+}
+
+type CatalogFilter struct {
+	ProductFilter
+	Sort   string `form:"sort" binding:"omitempty,oneof=name price price_desc newest"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+}
+
+type SearchFilter struct {
+	ProductFilter
+	Product string `form:"product"`
+}
+
+type CatalogPage struct {
+	Products   []ProductDTO `json:"products"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	Total      int          `json:"total"`
+}
+
+//product with visible state for administrator
+type AdminProductDTO struct {
+	ProductDTO
+	Visible bool `json:"visible"`
+}
+
+type AdminCatalogPage struct {
+	Products   []AdminProductDTO `json:"products"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	Total      int               `json:"total"`
+}
+
+type Suggestions struct {
+	Products   []string `json:"products"`
+	Categories []string `json:"categories"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+//products count in range [From, To), no bound means infinity
+type RangeFacet struct {
+	From  *float64 `json:"from,omitempty"`
+	To    *float64 `json:"to,omitempty"`
+	Count int      `json:"count"`
+}
+
+//products count in price range [From, To)
+type PriceFacet struct {
+	From  *Money `json:"from,omitempty"`
+	To    *Money `json:"to,omitempty"`
+	Count int    `json:"count"`
+}
+
+type Facets struct {
+	Categories []FacetCount `json:"categories"`
+	Price      []PriceFacet `json:"price"`
+	Weight     []RangeFacet `json:"weight"`
+	Valume     []RangeFacet `json:"valume"`
+}
+
+type SearchResult struct {
+	Products []ProductDTO `json:"products"`
+	Facets   Facets       `json:"facets"`
+}
+
+type AdminSearchResult struct {
+	Products []AdminProductDTO `json:"products"`
+	Facets   Facets            `json:"facets"`
+}
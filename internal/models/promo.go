@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+//types of discount
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+//what discount is applied to
+const (
+	ScopeCart     = "cart"
+	ScopeCategory = "category"
+	ScopeProduct  = "product"
+)
+
+//promo code or automatic discount without code,
+//value is percent or amount in base currency, targets are category names or product ids
+type Promo struct {
+	ID           uuid.UUID `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()"`
+	Code         *string   `gorm:"type:varchar(50); unique"`
+	Name         string    `gorm:"type:varchar(150); not null"`
+	Type         string    `gorm:"type:varchar(10); not null"`
+	Value        int64     `gorm:"not null"`
+	Scope        string    `gorm:"type:varchar(10); not null"`
+	Targets      []string  `gorm:"type:text[]"`
+	MinTotal     int64     `gorm:"not null; default:0"`
+	StartsAt     *time.Time
+	EndsAt       *time.Time
+	UsageLimit   *int64
+	PerUserLimit *int64
+	Used         int64     `gorm:"not null; default:0"`
+	Stackable    bool      `gorm:"default:false"`
+	Active       bool      `gorm:"default:true"`
+	CreatedAt    time.Time `gorm:"default:now()"`
+}
+
+//use of promo by order, it is counted for usage limits
+type PromoUsage struct {
+	ID        uuid.UUID `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()"`
+	PromoID   uuid.UUID `gorm:"type:uuid; not null; index"`
+	UserID    uuid.UUID `gorm:"type:uuid; not null"`
+	OrderID   uuid.UUID `gorm:"type:uuid; not null; index"`
+	Amount    int64     `gorm:"not null"`
+	CreatedAt time.Time `gorm:"default:now()"`
+}
+
+//promo code entered for cart of user
+type CartPromo struct {
+	UserID  uuid.UUID `gorm:"primary_key; type:uuid"`
+	PromoID uuid.UUID `gorm:"type:uuid; not null"`
+}
+
+type PromoDTO struct {
+	ID string `json:"id,omitempty"`
+	//promo without code is applied to all carts and shown in catalog
+	Code    string   `json:"code,omitempty"`
+	Name    string   `json:"name" binding:"required"`
+	Type    string   `json:"type" binding:"required,oneof=percent fixed"`
+	Percent int64    `json:"percent,omitempty"`
+	Amount  *Money   `json:"amount,omitempty"`
+	Scope   string   `json:"scope" binding:"required,oneof=cart category product"`
+	Targets []string `json:"targets,omitempty"`
+	//cart total without discounts from which promo works
+	MinTotal     *Money     `json:"min_total,omitempty"`
+	StartsAt     *time.Time `json:"starts_at,omitempty"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+	UsageLimit   *int64     `json:"usage_limit,omitempty" binding:"omitempty,min=1"`
+	PerUserLimit *int64     `json:"per_user_limit,omitempty" binding:"omitempty,min=1"`
+	Used         int64      `json:"used"`
+	//stackable discounts are summed up, not stackable discount is used alone
+	Stackable bool `json:"stackable"`
+	//promo without active is active
+	Active *bool `json:"active"`
+}
+
+type PromoCode struct {
+	Code string `json:"code" binding:"required"`
+}
+
+//discount applied to cart or order
+type AppliedDiscount struct {
+	PromoID string `json:"-"`
+	Code    string `json:"code,omitempty"`
+	Name    string `json:"name"`
+	Amount  Money  `json:"amount"`
+}
@@ -0,0 +1,34 @@
+package models
+
+//total sizes of goods, weight in kg and valume in litres
+type Parcel struct {
+	Weight float64 `json:"weight"`
+	Valume float64 `json:"valume"`
+}
+
+//add quantity of goods with sizes of one unit
+func (p *Parcel) Add(weight, valume float64, quantity int64) {
+	p.Weight += weight * float64(quantity)
+	p.Valume += valume * float64(quantity)
+}
+
+//price of delivery by tariff, chargeable weight is weight used for price
+type ShippingQuote struct {
+	Zone             string  `json:"zone"`
+	Tariff           string  `json:"tariff"`
+	Type             string  `json:"type"`
+	ChargeableWeight float64 `json:"chargeable_weight"`
+	Price            Money   `json:"price"`
+}
+
+type ShippingQuotes struct {
+	Region string `json:"region,omitempty"`
+	Parcel
+	//valume converted to weight by default divisor
+	VolumetricWeight float64         `json:"volumetric_weight"`
+	Quotes           []ShippingQuote `json:"quotes"`
+}
+
+type ShippingFilter struct {
+	Region string `form:"region"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+type Warehouse struct {
+	ID      uuid.UUID `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()" json:"id"`
+	Name    string    `gorm:"type:varchar(150); not null; unique" json:"name" binding:"required"`
+	Address string    `gorm:"type:varchar(255)" json:"address,omitempty"`
+}
+
+//quantity of product or its variant in warehouse
+type Stock struct {
+	WarehouseID uuid.UUID  `gorm:"type:uuid; not null" json:"warehouse_id"`
+	ProductID   uuid.UUID  `gorm:"type:uuid; not null; index" json:"product_id"`
+	VariantID   *uuid.UUID `gorm:"type:uuid; index" json:"variant_id,omitempty"`
+	Quantity    int64      `gorm:"not null; check:quantity >= 0" json:"quantity"`
+}
+
+//record of stock ledger, records are never changed or deleted
+type StockMovement struct {
+	ID          uuid.UUID  `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()" json:"id"`
+	WarehouseID uuid.UUID  `gorm:"type:uuid; not null; index" json:"warehouse_id"`
+	ProductID   uuid.UUID  `gorm:"type:uuid; not null; index" json:"product_id"`
+	VariantID   *uuid.UUID `gorm:"type:uuid" json:"variant_id,omitempty"`
+	Delta       int64      `gorm:"not null" json:"delta"`
+	Quantity    int64      `gorm:"not null" json:"quantity"`
+	Reason      string     `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	UserID      *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	OrderID     *uuid.UUID `gorm:"type:uuid; index" json:"order_id,omitempty"`
+	CreatedAt   time.Time  `gorm:"default:now(); index" json:"created_at"`
+}
+
+//change of stock, positive delta is receipt and negative is write-off
+type StockAdjustment struct {
+	WarehouseID uuid.UUID  `json:"warehouse_id" binding:"required"`
+	ProductID   uuid.UUID  `json:"product_id" binding:"required"`
+	VariantID   *uuid.UUID `json:"variant_id,omitempty"`
+	Delta       int64      `json:"delta" binding:"required"`
+	Reason      string     `json:"reason,omitempty"`
+}
+
+type MovementFilter struct {
+	ProductID   string `form:"product_id" binding:"omitempty,uuid"`
+	WarehouseID string `form:"warehouse_id" binding:"omitempty,uuid"`
+	Limit       int    `form:"limit"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import uuid "github.com/gofrs/uuid"
+
+//sku of product with own price, sizes and barcode, options of sku are set by option names of product
+type Variant struct {
+	ID        uuid.UUID `gorm:"primary_key; unique; type:uuid; column:id; default:uuid_generate_v4()"`
+	ProductID uuid.UUID `gorm:"type:uuid; not null; index"`
+	Options   []byte    `gorm:"type:jsonb; not null"`
+	Weight    float64   `gorm:"not null"`
+	Valume    float64   `gorm:"not null"`
+	Price     int64     `gorm:"not null"`
+	Barcode   *string   `gorm:"type:varchar(20); unique"`
+	Visible   bool      `gorm:"default:true"`
+}
+
+type VariantDTO struct {
+	ID       string            `json:"id,omitempty"`
+	Options  map[string]string `json:"options" binding:"required"`
+	Weight   float64           `json:"weight" binding:"required"`
+	Valume   float64           `json:"valume" binding:"required"`
+	Price    Money             `json:"price" binding:"required"`
+	Discount *Money            `json:"discount_price,omitempty"`
+	Barcode  string            `json:"barcode,omitempty"`
+	//variant without visible is shown
+	Visible   *bool `json:"visible"`
+	InStock   bool  `json:"in_stock"`
+	Available int64 `json:"available_quantity"`
+}
+
+type VariantUpdate struct {
+	Options map[string]string `json:"options,omitempty"`
+	Weight  *float64          `json:"weight,omitempty"`
+	Valume  *float64          `json:"valume,omitempty"`
+	Price   *Money            `json:"price,omitempty"`
+	Barcode *string           `json:"barcode,omitempty"`
+	Visible *bool             `json:"visible,omitempty"`
+}
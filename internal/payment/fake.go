@@ -0,0 +1,180 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EMus88/Market/internal/models"
+)
+
+const (
+	//header with signature of webhook: t=<unix time>,v1=<hex hmac of "t.body">
+	SignatureHeader = "X-Fake-Signature"
+	//webhook older than tolerance is rejected to prevent replay
+	signatureTolerance = 5 * time.Minute
+)
+
+//statuses of fake intent
+const (
+	statusPending    = "requires_payment"
+	statusAuthorized = "authorized"
+	statusSucceeded  = "succeeded"
+	statusFailed     = "failed"
+	statusRefunded   = "refunded"
+	statusCancelled  = "canceled"
+)
+
+//sandbox provider which works in process, customer actions are simulated by Pay and Fail,
+//signed webhooks are sent to Notify
+type Fake struct {
+	secret  []byte
+	mu      sync.Mutex
+	intents map[string]*Intent
+	Notify  func(body []byte, header http.Header)
+}
+
+//webhooks signed by empty secret can be forged, so it is not allowed
+func NewFake(secret string) (*Fake, error) {
+	if secret == "" {
+		return nil, ErrSecret
+	}
+	return &Fake{
+		secret:  []byte(secret),
+		intents: make(map[string]*Intent),
+	}, nil
+}
+
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+func (f *Fake) CreateIntent(orderID string, amount models.Money) (*Intent, error) {
+	intent := &Intent{
+		ID:      "pi_" + randomID(),
+		OrderID: orderID,
+		Amount:  amount,
+		Status:  statusPending,
+	}
+	intent.ConfirmURL = "/payments/fake/" + intent.ID + "/pay"
+	f.mu.Lock()
+	f.intents[intent.ID] = intent
+	f.mu.Unlock()
+	copy := *intent
+	return &copy, nil
+}
+
+//customer confirms payment, it is authorized but not charged
+func (f *Fake) Pay(intentID string) error {
+	return f.move(intentID, statusPending, statusAuthorized, EventAuthorized)
+}
+
+//customer payment is declined
+func (f *Fake) Fail(intentID string) error {
+	return f.move(intentID, statusPending, statusFailed, EventFailed)
+}
+
+func (f *Fake) Capture(intentID string) error {
+	return f.move(intentID, statusAuthorized, statusSucceeded, EventSucceeded)
+}
+
+func (f *Fake) Cancel(intentID string) error {
+	return f.move(intentID, statusAuthorized, statusCancelled, EventCancelled)
+}
+
+//only full refund is supported
+func (f *Fake) Refund(intentID string, amount models.Money) error {
+	f.mu.Lock()
+	intent, ok := f.intents[intentID]
+	if ok && intent.Amount != amount {
+		f.mu.Unlock()
+		return ErrState
+	}
+	f.mu.Unlock()
+	return f.move(intentID, statusSucceeded, statusRefunded, EventRefunded)
+}
+
+//change status of intent and send webhook, repeated change is not an error
+func (f *Fake) move(intentID, from, to, event string) error {
+	f.mu.Lock()
+	intent, ok := f.intents[intentID]
+	if !ok {
+		f.mu.Unlock()
+		return ErrIntent
+	}
+	if intent.Status == to {
+		f.mu.Unlock()
+		return nil
+	}
+	if intent.Status != from {
+		f.mu.Unlock()
+		return ErrState
+	}
+	intent.Status = to
+	e := Event{ID: "evt_" + randomID(), Type: event, IntentID: intent.ID, OrderID: intent.OrderID, Amount: intent.Amount}
+	f.mu.Unlock()
+	//webhook is sent without lock, handler can call provider again
+	if f.Notify != nil {
+		body, header := f.Webhook(e, time.Now())
+		f.Notify(body, header)
+	}
+	return nil
+}
+
+//signed webhook request of event
+func (f *Fake) Webhook(e Event, at time.Time) ([]byte, http.Header) {
+	body, _ := json.Marshal(e)
+	t := strconv.FormatInt(at.Unix(), 10)
+	header := http.Header{}
+	header.Set(SignatureHeader, fmt.Sprintf("t=%s,v1=%s", t, f.sign(t, body)))
+	return body, header
+}
+
+func (f *Fake) VerifyWebhook(body []byte, header http.Header) (*Event, error) {
+	var t, signature string
+	for _, part := range strings.Split(header.Get(SignatureHeader), ",") {
+		switch {
+		case strings.HasPrefix(part, "t="):
+			t = part[2:]
+		case strings.HasPrefix(part, "v1="):
+			signature = part[3:]
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return nil, ErrSignature
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > signatureTolerance || age < -signatureTolerance {
+		return nil, ErrSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(f.sign(t, body))) {
+		return nil, ErrSignature
+	}
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil || e.ID == "" || e.IntentID == "" {
+		return nil, ErrSignature
+	}
+	return &e, nil
+}
+
+func (f *Fake) sign(t string, body []byte) string {
+	mac := hmac.New(sha256.New, f.secret)
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
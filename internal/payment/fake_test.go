@@ -0,0 +1,90 @@
+package payment
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/go-playground/assert"
+)
+
+func Test_VerifyWebhook(t *testing.T) {
+	fake, _ := NewFake("secret")
+	event := Event{ID: "evt_1", Type: EventSucceeded, IntentID: "pi_1", Amount: models.NewMoney(100, models.BaseCurrency)}
+	body, header := fake.Webhook(event, time.Now())
+	_, old := fake.Webhook(event, time.Now().Add(-time.Hour))
+	otherFake, _ := NewFake("other")
+	_, other := otherFake.Webhook(event, time.Now())
+	tests := []struct {
+		name   string
+		body   []byte
+		header http.Header
+		err    error
+	}{
+		{name: "Ok", body: body, header: header},
+		{name: "Changed body", body: append(body, ' '), header: header, err: ErrSignature},
+		{name: "Other secret", body: body, header: other, err: ErrSignature},
+		{name: "Old webhook", body: body, header: old, err: ErrSignature},
+		{name: "No signature", body: body, header: http.Header{}, err: ErrSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := fake.VerifyWebhook(tt.body, tt.header)
+			assert.Equal(t, err, tt.err)
+			if err == nil {
+				assert.Equal(t, *e, event)
+			}
+		})
+	}
+}
+
+func Test_NewFake(t *testing.T) {
+	_, err := NewFake("")
+	assert.Equal(t, err, ErrSecret)
+	_, err = New("fake", "")
+	assert.Equal(t, err, ErrSecret)
+}
+
+func Test_FakeFlow(t *testing.T) {
+	fake, _ := NewFake("secret")
+	var events []string
+	fake.Notify = func(body []byte, header http.Header) {
+		e, err := fake.VerifyWebhook(body, header)
+		assert.Equal(t, err, nil)
+		events = append(events, e.Type)
+		//service captures payment right from webhook
+		if e.Type == EventAuthorized {
+			assert.Equal(t, fake.Capture(e.IntentID), nil)
+		}
+	}
+	amount := models.NewMoney(100, models.BaseCurrency)
+	intent, err := fake.CreateIntent("order", amount)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, fake.Refund(intent.ID, amount), ErrState)
+	assert.Equal(t, fake.Pay(intent.ID), nil)
+	assert.Equal(t, fake.Pay(intent.ID), ErrState)
+	assert.Equal(t, fake.Refund(intent.ID, amount), nil)
+	assert.Equal(t, fake.Refund(intent.ID, amount), nil)
+	assert.Equal(t, fake.Pay("pi_unknown"), ErrIntent)
+	assert.Equal(t, events, []string{EventAuthorized, EventSucceeded, EventRefunded})
+}
+
+func Test_FakeCancel(t *testing.T) {
+	fake, _ := NewFake("secret")
+	var events []string
+	fake.Notify = func(body []byte, header http.Header) {
+		e, err := fake.VerifyWebhook(body, header)
+		assert.Equal(t, err, nil)
+		events = append(events, e.Type)
+	}
+	intent, err := fake.CreateIntent("order", models.NewMoney(100, models.BaseCurrency))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, fake.Cancel(intent.ID), ErrState)
+	assert.Equal(t, fake.Pay(intent.ID), nil)
+	assert.Equal(t, fake.Cancel(intent.ID), nil)
+	assert.Equal(t, fake.Cancel(intent.ID), nil)
+	assert.Equal(t, fake.Capture(intent.ID), ErrState)
+	assert.Equal(t, events, []string{EventAuthorized, EventCancelled})
+}
@@ -0,0 +1,66 @@
+package payment
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EMus88/Market/internal/models"
+)
+
+//types of webhook events
+const (
+	EventAuthorized = "payment.authorized"
+	EventSucceeded  = "payment.succeeded"
+	EventFailed     = "payment.failed"
+	EventCancelled  = "payment.canceled"
+	EventRefunded   = "refund.succeeded"
+)
+
+var (
+	ErrProvider  = errors.New("error: unknown payment provider")
+	ErrIntent    = errors.New("error: payment intent not found")
+	ErrState     = errors.New("error: payment intent is in wrong state")
+	ErrSignature = errors.New("error: not valid webhook signature")
+	ErrSecret    = errors.New("error: secret of payment provider is not set")
+)
+
+//payment of order created in provider, customer pays it by confirm url
+type Intent struct {
+	ID         string       `json:"id"`
+	OrderID    string       `json:"order_id"`
+	Amount     models.Money `json:"amount"`
+	Status     string       `json:"status"`
+	ConfirmURL string       `json:"confirm_url,omitempty"`
+}
+
+//verified webhook event of provider
+type Event struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	IntentID string       `json:"intent_id"`
+	OrderID  string       `json:"order_id"`
+	Amount   models.Money `json:"amount"`
+}
+
+//payment provider, every provider is added as implementation of this interface
+type Gateway interface {
+	Name() string
+	CreateIntent(orderID string, amount models.Money) (*Intent, error)
+	//charge authorized payment
+	Capture(intentID string) error
+	//release authorized payment without charge
+	Cancel(intentID string) error
+	Refund(intentID string, amount models.Money) error
+	//check signature of webhook request and parse its event
+	VerifyWebhook(body []byte, header http.Header) (*Event, error)
+}
+
+//gateway of provider from config
+func New(provider, secret string) (Gateway, error) {
+	switch provider {
+	case "fake", "":
+		return NewFake(secret)
+	default:
+		return nil, ErrProvider
+	}
+}
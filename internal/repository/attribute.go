@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+)
+
+//attributes of category with attributes inherited from parent categories
+const attributesQuery = `WITH RECURSIVE tree AS (
+		SELECT id,parent_id FROM categories WHERE %s=$1
+		UNION ALL
+		SELECT c.id,c.parent_id FROM categories c JOIN tree ON c.id=tree.parent_id)
+	SELECT a.id,a.category_id,a.name,a.type,a.unit,a.required
+	FROM category_attributes a
+	JOIN tree ON a.category_id=tree.id
+	ORDER BY a.name;`
+
+func (r *Repository) GetAttributes(id uuid.UUID) ([]models.CategoryAttribute, error) {
+	return r.getAttributes(fmt.Sprintf(attributesQuery, "id"), id)
+}
+
+func (r *Repository) GetAttributesByName(category string) ([]models.CategoryAttribute, error) {
+	return r.getAttributes(fmt.Sprintf(attributesQuery, "category"), category)
+}
+
+func (r *Repository) getAttributes(q string, arg interface{}) ([]models.CategoryAttribute, error) {
+	attributes := []models.CategoryAttribute{}
+	rows, err := r.db.Query(context.Background(), q, arg)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a models.CategoryAttribute
+		if err := rows.Scan(&a.ID, &a.CategoryID, &a.Name, &a.Type, &a.Unit, &a.Required); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		attributes = append(attributes, a)
+	}
+	return attributes, nil
+}
+
+func (r *Repository) AddAttribute(a *models.CategoryAttribute) error {
+	q := `INSERT INTO category_attributes(category_id,name,type,unit,required)
+ 		VALUES($1,$2,$3,$4,$5)
+RETURNING id;`
+	err := r.db.QueryRow(context.Background(), q, a.CategoryID, a.Name, a.Type, a.Unit, a.Required).Scan(&a.ID)
+	if err != nil {
+		r.logger.Error(err)
+		if isPgError(err, "23505") {
+			return ErrAlreadyExist
+		}
+		if isPgError(err, "23503") {
+			return ErrNotFound
+		}
+		return ErrInternal
+	}
+	return nil
+}
+
+func (r *Repository) DeleteAttribute(category uuid.UUID, name string) error {
+	q := `DELETE FROM category_attributes
+		WHERE category_id=$1 AND name=$2;`
+	tag, err := r.db.Exec(context.Background(), q, category, name)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
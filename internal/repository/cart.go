@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+//add visible product to cart or increase its quantity, product with options is added only by variant
+func (r *Repository) AddToCart(user uuid.UUID, item *models.CartItemDTO) error {
+	var id string
+	q := `INSERT INTO cart_items(user_id,product_id,variant_id,quantity,price)
+	SELECT $1::uuid,p.id,v.id,$4::bigint,coalesce(v.price,p.price)
+	FROM products p
+	JOIN visible_categories c ON p.category_id=c.id
+	LEFT JOIN variants v ON v.id=$3 AND v.product_id=p.id AND v.visible=true
+		WHERE p.id=$2 AND p.visible=true
+			AND (($3::uuid IS NULL AND coalesce(cardinality(p.options),0)=0) OR v.id IS NOT NULL)
+	ON CONFLICT (user_id,product_id,coalesce(variant_id,'00000000-0000-0000-0000-000000000000'))
+	DO UPDATE SET quantity=cart_items.quantity+EXCLUDED.quantity, price=EXCLUDED.price
+RETURNING id;`
+	err := r.db.QueryRow(context.Background(), q, user, item.ProductID, item.VariantID, item.Quantity).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//set quantity of cart line, remembered price is updated to current
+func (r *Repository) UpdateCartItem(user, id uuid.UUID, quantity int64) error {
+	q := `UPDATE cart_items ci
+	SET quantity=$1, price=coalesce((SELECT price FROM variants WHERE id=ci.variant_id),
+		(SELECT price FROM products WHERE id=ci.product_id))
+		WHERE id=$2 AND user_id=$3;`
+	tag, err := r.db.Exec(context.Background(), q, quantity, id, user)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) DeleteCartItem(user, id uuid.UUID) error {
+	q := `DELETE FROM cart_items
+		WHERE id=$1 AND user_id=$2;`
+	tag, err := r.db.Exec(context.Background(), q, id, user)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+//lines of cart with current price, visibility and stock of products
+func (r *Repository) GetCartLines(user uuid.UUID) ([]models.CartLine, error) {
+	lines := []models.CartLine{}
+	q := `SELECT ci.id::text,ci.product_id::text,coalesce(ci.variant_id::text,''),p.name,coalesce(v.options,'{}'),
+		(SELECT category FROM categories WHERE id=p.category_id),
+		coalesce(v.price,p.price),ci.price,ci.quantity,coalesce(v.weight,p.weight),coalesce(v.valume,p.valume),
+		p.visible AND coalesce(v.visible,true) AND EXISTS (SELECT 1 FROM visible_categories c WHERE c.id=p.category_id),
+		CASE WHEN ci.variant_id IS NULL
+			THEN (SELECT coalesce(sum(quantity),0) FROM stocks WHERE product_id=ci.product_id AND variant_id IS NULL)
+			ELSE (SELECT coalesce(sum(quantity),0) FROM stocks WHERE variant_id=ci.variant_id) END::bigint
+	FROM cart_items ci
+	JOIN products p ON p.id=ci.product_id
+	LEFT JOIN variants v ON v.id=ci.variant_id
+		WHERE ci.user_id=$1
+	ORDER BY ci.created_at, ci.id;`
+	rows, err := r.db.Query(context.Background(), q, user)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var line models.CartLine
+		var price, added int64
+		err := rows.Scan(&line.ID, &line.ProductID, &line.VariantID, &line.Name, &line.Options, &line.Category,
+			&price, &added, &line.Quantity, &line.Weight, &line.Valume, &line.Visible, &line.Available)
+		if err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		line.Price = models.NewMoney(price, models.BaseCurrency)
+		line.AddedPrice = models.NewMoney(added, models.BaseCurrency)
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
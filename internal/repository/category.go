@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+func (r *Repository) GetCategories(all bool) ([]models.Category, error) {
+	var categories []models.Category
+	q := `SELECT id,category,visible,parent_id
+	FROM categories
+		WHERE $1 OR id IN (SELECT id FROM visible_categories)
+	ORDER BY category;`
+	rows, err := r.db.Query(context.Background(), q, all)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var category models.Category
+		if err := rows.Scan(&category.ID, &category.Name, &category.Visible, &category.ParentID); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
+
+func (r *Repository) RenameCategory(id uuid.UUID, name string) error {
+	q := `UPDATE categories
+	SET category=$1
+		WHERE id=$2;`
+	tag, err := r.db.Exec(context.Background(), q, name, id)
+	if err != nil {
+		r.logger.Error(err)
+		if isPgError(err, "23505") {
+			return ErrAlreadyExist
+		}
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+//category can be deleted only if no products and subcategories reference it
+func (r *Repository) DeleteCategory(id uuid.UUID) error {
+	var deleted int
+	var exist bool
+	q := `WITH deleted AS (
+		DELETE FROM categories
+			WHERE id=$1 AND NOT EXISTS (SELECT 1 FROM products WHERE category_id=$1)
+				AND NOT EXISTS (SELECT 1 FROM categories WHERE parent_id=$1)
+		RETURNING id)
+	SELECT (SELECT count(*) FROM deleted), EXISTS (SELECT 1 FROM categories WHERE id=$1);`
+	if err := r.db.QueryRow(context.Background(), q, id).Scan(&deleted, &exist); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if deleted == 0 {
+		if exist {
+			return ErrInUse
+		}
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) ChangeCategoryVisible(v *models.Visible) error {
+	q := `UPDATE categories
+	SET visible=$1
+		WHERE category=$2;`
+	tag, err := r.db.Exec(context.Background(), q, v.Visible, v.Name)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+//get visible categories as tree, root=nil returns whole tree
+func (r *Repository) GetCategoryTree(root *uuid.UUID) ([]*models.CategoryNode, error) {
+	q := `WITH RECURSIVE tree AS (
+		SELECT id, category, parent_id FROM visible_categories
+			WHERE ($1::uuid IS NULL AND parent_id IS NULL) OR id=$1
+		UNION ALL
+		SELECT c.id, c.category, c.parent_id FROM visible_categories c
+			JOIN tree ON c.parent_id=tree.id)
+	SELECT id, category, parent_id FROM tree
+	ORDER BY category;`
+	rows, err := r.db.Query(context.Background(), q, root)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	var list []models.Category
+	for rows.Next() {
+		var category models.Category
+		if err := rows.Scan(&category.ID, &category.Name, &category.ParentID); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		list = append(list, category)
+	}
+	if root != nil && len(list) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return buildTree(list, root), nil
+}
+
+//link categories with their parents
+func buildTree(list []models.Category, root *uuid.UUID) []*models.CategoryNode {
+	nodes := make(map[uuid.UUID]*models.CategoryNode, len(list))
+	for _, category := range list {
+		nodes[category.ID] = &models.CategoryNode{ID: category.ID, Name: category.Name}
+	}
+	var tree []*models.CategoryNode
+	for _, category := range list {
+		node := nodes[category.ID]
+		if root != nil && category.ID == *root {
+			tree = append(tree, node)
+			continue
+		}
+		if category.ParentID == nil {
+			tree = append(tree, node)
+			continue
+		}
+		if parent, ok := nodes[*category.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	return tree
+}
+
+//move category with its subtree to new parent, parent=nil makes it root
+func (r *Repository) MoveCategory(id uuid.UUID, parent *uuid.UUID) error {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	//concurrent moves could create a cycle together, so serialize them
+	if _, err := tx.Exec(ctx, "LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE;"); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if parent != nil {
+		var cycle bool
+		q := `WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id=$1
+			UNION ALL
+			SELECT c.id FROM categories c JOIN subtree ON c.parent_id=subtree.id)
+		SELECT EXISTS (SELECT 1 FROM subtree WHERE id=$2);`
+		if err := tx.QueryRow(ctx, q, id, *parent).Scan(&cycle); err != nil {
+			r.logger.Error(err)
+			return ErrInternal
+		}
+		if cycle {
+			return ErrCycle
+		}
+	}
+	q := `UPDATE categories
+	SET parent_id=$1
+		WHERE id=$2;`
+	tag, err := tx.Exec(ctx, q, parent, id)
+	if err != nil {
+		r.logger.Error(err)
+		if isPgError(err, "23503") {
+			return ErrNoCategory
+		}
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+func (r *Repository) SetProductPrice(id uuid.UUID, price models.Money) error {
+	q := `INSERT INTO product_prices(product_id,currency,amount)
+ 		VALUES($1,$2,$3)
+	ON CONFLICT (product_id,currency) DO UPDATE
+	SET amount=EXCLUDED.amount;`
+	_, err := r.db.Exec(context.Background(), q, id, price.Currency, price.Amount)
+	if err != nil {
+		r.logger.Error(err)
+		if isPgError(err, "23503") {
+			return ErrNotFound
+		}
+		return ErrInternal
+	}
+	return nil
+}
+
+func (r *Repository) DeleteProductPrice(id uuid.UUID, currency string) error {
+	q := `DELETE FROM product_prices
+		WHERE product_id=$1 AND currency=$2;`
+	tag, err := r.db.Exec(context.Background(), q, id, currency)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) GetRates() ([]models.ExchangeRate, error) {
+	rates := []models.ExchangeRate{}
+	q := `SELECT currency,rate::text,updated_at
+	FROM exchange_rates
+	ORDER BY currency;`
+	rows, err := r.db.Query(context.Background(), q)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rate models.ExchangeRate
+		if err := rows.Scan(&rate.Currency, &rate.Rate, &rate.UpdatedAt); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func (r *Repository) SetRate(rate *models.ExchangeRate) error {
+	q := `INSERT INTO exchange_rates(currency,rate,updated_at)
+ 		VALUES($1,$2::numeric,now())
+	ON CONFLICT (currency) DO UPDATE
+	SET rate=EXCLUDED.rate, updated_at=EXCLUDED.updated_at;`
+	if _, err := r.db.Exec(context.Background(), q, rate.Currency, rate.Rate); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//set prices of products in currency, explicit price of product is used if it is set,
+//otherwise base price is converted by exchange rate
+func (r *Repository) ConvertPrices(products []models.ProductDTO, currency string) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	q := `SELECT product_id::text,amount
+	FROM product_prices
+		WHERE currency=$1 AND product_id=ANY($2::uuid[]);`
+	rows, err := r.db.Query(context.Background(), q, currency, ids)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	explicit := make(map[string]int64)
+	for rows.Next() {
+		var id string
+		var amount int64
+		if err := rows.Scan(&id, &amount); err != nil {
+			rows.Close()
+			r.logger.Error(err)
+			return ErrInternal
+		}
+		explicit[id] = amount
+	}
+	rows.Close()
+
+	//variants have no explicit prices and are always converted by rate
+	needRate := len(explicit) < len(products)
+	for _, product := range products {
+		needRate = needRate || len(product.Variants) > 0
+	}
+	var rateText string
+	q = `SELECT rate::text
+	FROM exchange_rates
+		WHERE currency=$1;`
+	if err := r.db.QueryRow(context.Background(), q, currency).Scan(&rateText); err != nil && needRate {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNoRate
+		}
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	for i := range products {
+		for j := range products[i].Variants {
+			variant := &products[i].Variants[j]
+			if err := convertPrice(&variant.Price, rateText, currency); err != nil {
+				r.logger.Error(err)
+				return err
+			}
+			if variant.Discount != nil {
+				if err := convertPrice(variant.Discount, rateText, currency); err != nil {
+					r.logger.Error(err)
+					return err
+				}
+			}
+		}
+		if amount, ok := explicit[products[i].ID]; ok {
+			//discount keeps its share of explicit price
+			if d := products[i].Discount; d != nil && products[i].Price.Amount > 0 {
+				share, err := d.Share(amount, products[i].Price.Amount, currency)
+				if err != nil {
+					r.logger.Error(err)
+					return err
+				}
+				*d = share
+			}
+			products[i].Price = models.NewMoney(amount, currency)
+			continue
+		}
+		if d := products[i].Discount; d != nil {
+			if err := convertPrice(d, rateText, currency); err != nil {
+				r.logger.Error(err)
+				return err
+			}
+		}
+		if err := convertPrice(&products[i].Price, rateText, currency); err != nil {
+			r.logger.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+func convertPrice(price *models.Money, rateText, currency string) error {
+	rate, err := models.ParseRate(rateText)
+	if err != nil {
+		return ErrNoRate
+	}
+	if *price, err = price.Convert(rate, currency); err != nil {
+		return ErrInternal
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgconn"
+)
+
+var (
+	ErrNotFound      = errors.New("error: not found")
+	ErrAlreadyExist  = errors.New("error: already exist")
+	ErrNoCategory    = errors.New("error: category not found")
+	ErrInUse         = errors.New("error: category is not empty")
+	ErrCycle         = errors.New("error: category can not be moved into itself")
+	ErrBadCursor     = errors.New("error: not valid cursor")
+	ErrNoRate        = errors.New("error: exchange rate not found")
+	ErrNoStock       = errors.New("error: not enough stock")
+	ErrStatusChanged = errors.New("error: order status was changed")
+	ErrCartChanged   = errors.New("error: cart was changed")
+	ErrPromoUsed     = errors.New("error: promo can not be used anymore")
+	ErrImageSet      = errors.New("error: list must contain every image of product once")
+	ErrInternal      = errors.New("error: internal db error")
+)
+
+//check postgres error code
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == code
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+)
+
+//count of products read from db at once on export
+const exportBatch = 500
+
+//names of all attributes which are set in products
+func (r *Repository) GetAttributeNames() ([]string, error) {
+	q := `SELECT DISTINCT jsonb_object_keys(attributes) AS name
+	FROM products
+	ORDER BY name;`
+	rows, err := r.db.Query(context.Background(), q)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+//pass products with variants, images and stock to fn in order of id, hidden products are passed if all is true,
+//products are read by batches, so only one batch is kept in memory
+func (r *Repository) ExportProducts(ctx context.Context, all bool, fn func(*models.ProductDTO) error) error {
+	after := uuid.Nil.String()
+	for {
+		q := `SELECT products.id,name,weight,valume,description,photo,price,products.visible,category,attributes,options,` + availableColumn + `
+		FROM products
+		JOIN ` + categoriesView(all) + ` categories ON category_id=categories.id
+			WHERE products.id>$1::uuid AND ($3 OR products.visible=true)
+		ORDER BY products.id
+		LIMIT $2;`
+		rows, err := r.db.Query(ctx, q, after, exportBatch, all)
+		if err != nil {
+			r.logger.Error(err)
+			return ErrInternal
+		}
+		products := make([]models.ProductDTO, 0, exportBatch)
+		for rows.Next() {
+			var product models.ProductDTO
+			var price int64
+			if err := rows.Scan(&product.ID, &product.Name, &product.Weight, &product.Valume, &product.Description, &product.Photo, &price, &product.Visible, &product.Category, &product.Attributes, &product.Options, &product.Available); err != nil {
+				rows.Close()
+				r.logger.Error(err)
+				return ErrInternal
+			}
+			product.Price = models.NewMoney(price, models.BaseCurrency)
+			product.InStock = product.Available > 0
+			products = append(products, product)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			r.logger.Error(err)
+			return ErrInternal
+		}
+		if err := r.attachVariants(products, all); err != nil {
+			return err
+		}
+		if err := r.attachImages(products); err != nil {
+			return err
+		}
+		for i := range products {
+			if err := fn(&products[i]); err != nil {
+				return err
+			}
+		}
+		if len(products) < exportBatch {
+			return nil
+		}
+		after = products[len(products)-1].ID
+	}
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+//sort order of catalog with type of column for keyset cursor
+type catalogSort struct {
+	column string
+	cast   string
+	desc   bool
+}
+
+var catalogSorts = map[string]catalogSort{
+	"name":       {column: "name", cast: "text"},
+	"price":      {column: "price", cast: "bigint"},
+	"price_desc": {column: "price", cast: "bigint", desc: true},
+	"newest":     {column: "created_at", cast: "timestamptz", desc: true},
+}
+
+//position of last product on page
+type cursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+//cursor with value of sort column type, so bad cursor is not sent to db
+func decodeCursor(s, cast string) (*cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, ErrBadCursor
+	}
+	if _, err := uuid.FromString(c.ID); err != nil {
+		return nil, ErrBadCursor
+	}
+	switch cast {
+	case "bigint":
+		_, err = strconv.ParseInt(c.Value, 10, 64)
+	case "timestamptz":
+		_, err = time.Parse(time.RFC3339Nano, c.Value)
+	default:
+		if !utf8.ValidString(c.Value) || strings.ContainsRune(c.Value, 0) {
+			err = ErrBadCursor
+		}
+	}
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	return &c, nil
+}
+
+//where clause with numbered arguments
+type where struct {
+	conds []string
+	args  []interface{}
+}
+
+//add condition, each %d in cond is replaced with number of next argument
+func (w *where) add(cond string, args ...interface{}) {
+	nums := make([]interface{}, len(args))
+	for i, arg := range args {
+		w.args = append(w.args, arg)
+		nums[i] = len(w.args)
+	}
+	w.conds = append(w.conds, fmt.Sprintf(cond, nums...))
+}
+
+func (w *where) String() string {
+	if len(w.conds) == 0 {
+		return "true"
+	}
+	return strings.Join(w.conds, " AND ")
+}
+
+//admin sees hidden categories too
+func categoriesView(all bool) string {
+	if all {
+		return "categories"
+	}
+	return "visible_categories"
+}
+
+//add conditions of product filter
+func (w *where) filter(f *models.ProductFilter, all bool) {
+	if f.Category != "" {
+		w.add(fmt.Sprintf(`category_id IN (WITH RECURSIVE tree AS (
+			SELECT id FROM %[1]s WHERE category=$%%d
+			UNION ALL
+			SELECT c.id FROM %[1]s c JOIN tree ON c.parent_id=tree.id)
+		SELECT id FROM tree)`, categoriesView(all)), f.Category)
+	}
+	if f.MinPrice != nil {
+		w.add("price>=$%d", f.MinPrice.Amount)
+	}
+	if f.MaxPrice != nil {
+		w.add("price<=$%d", f.MaxPrice.Amount)
+	}
+	if f.MinWeight != nil {
+		w.add("weight>=$%d", *f.MinWeight)
+	}
+	if f.MaxWeight != nil {
+		w.add("weight<=$%d", *f.MaxWeight)
+	}
+	if f.MinValume != nil {
+		w.add("valume>=$%d", *f.MinValume)
+	}
+	if f.MaxValume != nil {
+		w.add("valume<=$%d", *f.MaxValume)
+	}
+	if f.InStock {
+		w.add("EXISTS (SELECT 1 FROM stocks WHERE product_id=products.id AND quantity>0)")
+	}
+	for _, a := range f.Attributes {
+		if a.Value != nil {
+			w.add("products.attributes->>$%d=$%d", a.Name, *a.Value)
+		}
+		//range is applied only to number values
+		if a.Min != nil {
+			w.add(`CASE WHEN jsonb_typeof(products.attributes->$%d)='number'
+			THEN (products.attributes->>$%d)::numeric END>=$%d`, a.Name, a.Name, *a.Min)
+		}
+		if a.Max != nil {
+			w.add(`CASE WHEN jsonb_typeof(products.attributes->$%d)='number'
+			THEN (products.attributes->>$%d)::numeric END<=$%d`, a.Name, a.Name, *a.Max)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const imageColumns = `id::text,key,url,content_type,size,position,is_primary,status,sizes,coalesce(files,'{}')`
+
+//add images to the end of product images in one transaction, first image of product becomes primary
+func (r *Repository) AddImages(product uuid.UUID, images []*models.ImageDTO) error {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	if err := r.lockImages(ctx, tx, product); err != nil {
+		return err
+	}
+	for _, img := range images {
+		if img.Primary {
+			q := `UPDATE product_images SET is_primary=false
+				WHERE product_id=$1 AND is_primary;`
+			if _, err := tx.Exec(ctx, q, product); err != nil {
+				r.logger.Error(err)
+				return ErrInternal
+			}
+		}
+		q := `INSERT INTO product_images(product_id,key,url,content_type,size,position,is_primary)
+		SELECT $1::uuid,$2::text,$3::text,$4::text,$5::bigint,coalesce(max(position)+1,0),$6::boolean OR count(*)=0
+		FROM product_images
+			WHERE product_id=$1
+	RETURNING id::text,position,is_primary,status;`
+		err = tx.QueryRow(ctx, q, product, img.Key, img.URL, img.ContentType, img.Size, img.Primary).
+			Scan(&img.ID, &img.Position, &img.Primary, &img.Status)
+		if err != nil {
+			r.logger.Error(err)
+			return ErrInternal
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//images of product in their order
+func (r *Repository) GetImages(product uuid.UUID) ([]models.ImageDTO, error) {
+	images := []models.ImageDTO{}
+	q := `SELECT ` + imageColumns + `
+	FROM product_images
+		WHERE product_id=$1
+	ORDER BY position, id;`
+	rows, err := r.db.Query(context.Background(), q, product)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var img models.ImageDTO
+		if err := rows.Scan(&img.ID, &img.Key, &img.URL, &img.ContentType, &img.Size, &img.Position, &img.Primary, &img.Status, &img.Sizes, &img.Files); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+func (r *Repository) SetPrimaryImage(product, id uuid.UUID) error {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	if err := r.lockImages(ctx, tx, product); err != nil {
+		return err
+	}
+	//old primary is cleared first, only one primary image is allowed by index
+	q := `UPDATE product_images SET is_primary=false
+		WHERE product_id=$1 AND is_primary AND id<>$2;`
+	if _, err := tx.Exec(ctx, q, product, id); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	q = `UPDATE product_images SET is_primary=true
+		WHERE product_id=$1 AND id=$2;`
+	tag, err := tx.Exec(ctx, q, product, id)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//set order of images, list must contain every image of product once
+func (r *Repository) SortImages(product uuid.UUID, ids []uuid.UUID) error {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	if err := r.lockImages(ctx, tx, product); err != nil {
+		return err
+	}
+	var total, listed int
+	q := `SELECT count(*),count(*) FILTER (WHERE id=ANY($2::uuid[]))
+	FROM product_images
+		WHERE product_id=$1;`
+	if err := tx.QueryRow(ctx, q, product, ids).Scan(&total, &listed); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if total != len(ids) || listed != len(ids) {
+		return ErrImageSet
+	}
+	q = `UPDATE product_images SET position=array_position($2::uuid[],id)-1
+		WHERE product_id=$1;`
+	if _, err := tx.Exec(ctx, q, product, ids); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//delete image and return keys of its files, next image becomes primary instead of deleted one
+func (r *Repository) DeleteImage(product, id uuid.UUID) ([]string, error) {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	if err := r.lockImages(ctx, tx, product); err != nil {
+		return nil, err
+	}
+	var key string
+	var files []string
+	var primary bool
+	q := `DELETE FROM product_images
+		WHERE product_id=$1 AND id=$2
+RETURNING key,coalesce(files,'{}'),is_primary;`
+	if err := tx.QueryRow(ctx, q, product, id).Scan(&key, &files, &primary); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	if primary {
+		q = `UPDATE product_images SET is_primary=true
+			WHERE id=(SELECT id FROM product_images WHERE product_id=$1 ORDER BY position, id LIMIT 1);`
+		if _, err := tx.Exec(ctx, q, product); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	return append(files, key), nil
+}
+
+//take pending images for generating of sizes, images which are processed too long are taken again
+func (r *Repository) ClaimImages(limit int) ([]models.ImageDTO, error) {
+	images := []models.ImageDTO{}
+	q := `UPDATE product_images SET status='processing',processed_at=now()
+		WHERE id IN (
+			SELECT id FROM product_images
+				WHERE status='pending' OR (status='processing' AND processed_at<now()-interval '10 minutes')
+			ORDER BY created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+RETURNING ` + imageColumns + `;`
+	rows, err := r.db.Query(context.Background(), q, limit)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var img models.ImageDTO
+		if err := rows.Scan(&img.ID, &img.Key, &img.URL, &img.ContentType, &img.Size, &img.Position, &img.Primary, &img.Status, &img.Sizes, &img.Files); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+//save generated sizes and status of processed image, image could be deleted while it was processed
+func (r *Repository) SetImageSizes(id uuid.UUID, img *models.ImageDTO) error {
+	sizes := []byte("{}")
+	if img.Sizes != nil {
+		var err error
+		if sizes, err = json.Marshal(img.Sizes); err != nil {
+			return err
+		}
+	}
+	q := `UPDATE product_images SET status=$2,sizes=$3,files=$4,processed_at=now()
+		WHERE id=$1 AND status='processing';`
+	tag, err := r.db.Exec(context.Background(), q, id, img.Status, sizes, img.Files)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+//changes of images of one product are serialized by lock of product
+func (r *Repository) lockImages(ctx context.Context, tx pgx.Tx, product uuid.UUID) error {
+	var id string
+	q := `SELECT id::text
+	FROM products
+		WHERE id=$1
+	FOR UPDATE;`
+	if err := tx.QueryRow(ctx, q, product).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//embed images into products in their order
+func (r *Repository) attachImages(products []models.ProductDTO) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]string, len(products))
+	index := make(map[string]int, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+		index[product.ID] = i
+	}
+	q := `SELECT product_id::text,` + imageColumns + `
+	FROM product_images
+		WHERE product_id=ANY($1::uuid[])
+	ORDER BY position, id;`
+	rows, err := r.db.Query(context.Background(), q, ids)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var img models.ImageDTO
+		var productID string
+		if err := rows.Scan(&productID, &img.ID, &img.Key, &img.URL, &img.ContentType, &img.Size, &img.Position, &img.Primary, &img.Status, &img.Sizes, &img.Files); err != nil {
+			r.logger.Error(err)
+			return ErrInternal
+		}
+		i := index[productID]
+		products[i].Images = append(products[i].Images, img)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+//products with names from list, with their variants
+func (r *Repository) GetProductsByName(names []string) (map[string]models.ProductDTO, error) {
+	q := `SELECT products.id,name,weight,valume,description,photo,price,products.visible,category,attributes,options
+	FROM products
+	JOIN categories ON category_id=categories.id
+		WHERE name=ANY($1);`
+	rows, err := r.db.Query(context.Background(), q, names)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	var products []models.ProductDTO
+	for rows.Next() {
+		var product models.ProductDTO
+		var price int64
+		if err := rows.Scan(&product.ID, &product.Name, &product.Weight, &product.Valume, &product.Description, &product.Photo, &price, &product.Visible, &product.Category, &product.Attributes, &product.Options); err != nil {
+			rows.Close()
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		product.Price = models.NewMoney(price, models.BaseCurrency)
+		products = append(products, product)
+	}
+	rows.Close()
+	if err := r.attachVariants(products, true); err != nil {
+		return nil, err
+	}
+	byName := make(map[string]models.ProductDTO, len(products))
+	for _, product := range products {
+		byName[product.Name] = product
+	}
+	return byName, nil
+}
+
+//create new products and update existing ones by name in one transaction,
+//changes of prices are written to price history
+func (r *Repository) ImportProducts(rows []models.ImportRow, user *uuid.UUID) (*models.ImportReport, error) {
+	report := &models.ImportReport{Rows: len(rows)}
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	for _, row := range rows {
+		m := &row.Product
+		attributes := m.Attributes
+		if attributes == nil {
+			attributes = map[string]interface{}{}
+		}
+		var id uuid.UUID
+		var old int64
+		q := `SELECT id,price
+		FROM products
+			WHERE name=$1
+		FOR UPDATE;`
+		err := tx.QueryRow(ctx, q, m.Name).Scan(&id, &old)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			q = `INSERT INTO products(name,weight,valume,description,photo,price,visible,attributes,options,category_id)
+ 				VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,
+				(SELECT id FROM categories
+					WHERE category=$10));`
+			if _, err := tx.Exec(ctx, q, m.Name, m.Weight, m.Valume, m.Description, m.Photo, m.Price.Amount, m.Visible, attributes, m.Options, m.Category); err != nil {
+				return nil, r.importError(row.Row, err)
+			}
+			report.Created++
+		case err != nil:
+			return nil, r.importError(row.Row, err)
+		default:
+			q = `UPDATE products
+			SET weight=$2,valume=$3,description=$4,photo=$5,price=$6,visible=$7,attributes=$8,options=$9,
+				category_id=(SELECT id FROM categories WHERE category=$10)
+				WHERE id=$1;`
+			if _, err := tx.Exec(ctx, q, id, m.Weight, m.Valume, m.Description, m.Photo, m.Price.Amount, m.Visible, attributes, m.Options, m.Category); err != nil {
+				return nil, r.importError(row.Row, err)
+			}
+			if err := r.addPriceHistory(ctx, tx, id, old, m.Price.Amount, user, nil); err != nil {
+				return nil, err
+			}
+			report.Updated++
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	return report, nil
+}
+
+//category could be deleted after file was checked
+func (r *Repository) importError(row int, err error) error {
+	if isPgError(err, "23502") {
+		return fmt.Errorf("%w: row %d", ErrNoCategory, row)
+	}
+	r.logger.Error(err)
+	return fmt.Errorf("%w: row %d", ErrInternal, row)
+}
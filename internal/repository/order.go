@@ -0,0 +1,402 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+//create order from cart, write off stock, count uses of promos and clear cart in one transaction
+func (r *Repository) CreateOrder(user uuid.UUID, cart *models.Cart) (*models.OrderDTO, error) {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	//cart lines are removed first, concurrent checkout of same cart waits for them and finds nothing to remove;
+	//only bought lines are removed, line added to cart after reading stays there
+	lines := make([]string, 0, len(cart.Lines))
+	for _, line := range cart.Lines {
+		lines = append(lines, line.ID)
+	}
+	q := `DELETE FROM cart_items
+		WHERE user_id=$1 AND id=ANY($2::uuid[]);`
+	tag, err := tx.Exec(ctx, q, user, lines)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	if tag.RowsAffected() != int64(len(lines)) {
+		return nil, ErrCartChanged
+	}
+	order := models.OrderDTO{
+		UserID:   user.String(),
+		Status:   models.OrderCreated,
+		Discount: cart.Discount,
+		Total:    cart.Total,
+	}
+	var id uuid.UUID
+	q = `INSERT INTO orders(user_id,status,total,discount)
+ 		VALUES($1,$2,$3,$4)
+RETURNING id,created_at,updated_at;`
+	if err := tx.QueryRow(ctx, q, user, order.Status, order.Total.Amount, order.Discount.Amount).Scan(&id, &order.CreatedAt, &order.UpdatedAt); err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	order.ID = id.String()
+	for _, line := range cart.Lines {
+		item := models.OrderItemDTO{
+			ProductID: line.ProductID,
+			VariantID: line.VariantID,
+			Name:      line.Name,
+			Options:   line.Options,
+			Price:     line.Price,
+			Quantity:  line.Quantity,
+			Weight:    line.Weight,
+			Valume:    line.Valume,
+		}
+		options := item.Options
+		if options == nil {
+			options = map[string]string{}
+		}
+		q := `INSERT INTO order_items(order_id,product_id,variant_id,name,options,price,quantity,weight,valume)
+ 			VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9);`
+		_, err := tx.Exec(ctx, q, id, item.ProductID, nullUUID(item.VariantID), item.Name, options, item.Price.Amount, item.Quantity,
+			item.Weight, item.Valume)
+		if err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		if err := r.writeOff(ctx, tx, id, &user, item); err != nil {
+			return nil, err
+		}
+		order.Items = append(order.Items, item)
+	}
+	for _, d := range cart.Discounts {
+		if err := r.usePromo(ctx, tx, id, user, d); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.addHistory(ctx, tx, id, "", order.Status, &user); err != nil {
+		return nil, err
+	}
+	q = `DELETE FROM cart_promos
+		WHERE user_id=$1;`
+	if _, err := tx.Exec(ctx, q, user); err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	return &order, nil
+}
+
+//take quantity of item from warehouses with biggest stock first
+func (r *Repository) writeOff(ctx context.Context, tx pgx.Tx, order uuid.UUID, user *uuid.UUID, item models.OrderItemDTO) error {
+	type stock struct {
+		warehouse uuid.UUID
+		quantity  int64
+	}
+	var stocks []stock
+	q := `SELECT warehouse_id,quantity
+	FROM stocks
+		WHERE product_id=$1 AND variant_id IS NOT DISTINCT FROM $2::uuid AND quantity>0
+	ORDER BY quantity DESC, warehouse_id
+	FOR UPDATE;`
+	rows, err := tx.Query(ctx, q, item.ProductID, nullUUID(item.VariantID))
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	for rows.Next() {
+		var s stock
+		if err := rows.Scan(&s.warehouse, &s.quantity); err != nil {
+			rows.Close()
+			r.logger.Error(err)
+			return ErrInternal
+		}
+		stocks = append(stocks, s)
+	}
+	rows.Close()
+	product, err := uuid.FromString(item.ProductID)
+	if err != nil {
+		return ErrNotFound
+	}
+	var variant *uuid.UUID
+	if item.VariantID != "" {
+		v, err := uuid.FromString(item.VariantID)
+		if err != nil {
+			return ErrNotFound
+		}
+		variant = &v
+	}
+	need := item.Quantity
+	for _, s := range stocks {
+		if need == 0 {
+			break
+		}
+		take := s.quantity
+		if take > need {
+			take = need
+		}
+		movement := models.StockMovement{
+			WarehouseID: s.warehouse,
+			ProductID:   product,
+			VariantID:   variant,
+			Delta:       -take,
+			Reason:      "order",
+			UserID:      user,
+			OrderID:     &order,
+		}
+		if err := r.moveStock(ctx, tx, &movement); err != nil {
+			return err
+		}
+		need -= take
+	}
+	if need > 0 {
+		return ErrNoStock
+	}
+	return nil
+}
+
+//return stock written off by order
+func (r *Repository) restoreStock(ctx context.Context, tx pgx.Tx, order uuid.UUID, user *uuid.UUID, reason string) error {
+	var movements []models.StockMovement
+	q := `SELECT warehouse_id,product_id,variant_id,-sum(delta)::bigint
+	FROM stock_movements
+		WHERE order_id=$1
+	GROUP BY warehouse_id,product_id,variant_id
+	HAVING sum(delta)<>0;`
+	rows, err := tx.Query(ctx, q, order)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	for rows.Next() {
+		m := models.StockMovement{Reason: reason, UserID: user, OrderID: &order}
+		if err := rows.Scan(&m.WarehouseID, &m.ProductID, &m.VariantID, &m.Delta); err != nil {
+			rows.Close()
+			r.logger.Error(err)
+			return ErrInternal
+		}
+		movements = append(movements, m)
+	}
+	rows.Close()
+	for i := range movements {
+		if err := r.moveStock(ctx, tx, &movements[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repository) addHistory(ctx context.Context, tx pgx.Tx, order uuid.UUID, from, to string, user *uuid.UUID) error {
+	q := `INSERT INTO order_histories(order_id,from_status,to_status,user_id)
+ 		VALUES($1,$2,$3,$4);`
+	if _, err := tx.Exec(ctx, q, order, from, to, user); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//change status if it was not changed by somebody else, stock of cancelled order and of refunded order
+//which was not shipped is returned, promos are returned for both
+func (r *Repository) UpdateOrderStatus(id uuid.UUID, from, to string, user *uuid.UUID) error {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	if err := r.changeOrderStatus(ctx, tx, id, from, to, user); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+func (r *Repository) changeOrderStatus(ctx context.Context, tx pgx.Tx, id uuid.UUID, from, to string, user *uuid.UUID) error {
+	q := `UPDATE orders
+	SET status=$1, updated_at=now()
+		WHERE id=$2 AND status=$3;`
+	tag, err := tx.Exec(ctx, q, to, id, from)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrStatusChanged
+	}
+	if err := r.addHistory(ctx, tx, id, from, to, user); err != nil {
+		return err
+	}
+	if to != models.OrderCancelled && to != models.OrderRefunded {
+		return nil
+	}
+	//goods of shipped order are at customer, they come back to stock by adjustment
+	if from == models.OrderCreated || from == models.OrderPaid || from == models.OrderPacked {
+		if err := r.restoreStock(ctx, tx, id, user, "order "+to); err != nil {
+			return err
+		}
+	}
+	return r.restorePromos(ctx, tx, id)
+}
+
+//order with items and history, user limits search to own orders
+func (r *Repository) GetOrder(id uuid.UUID, user *uuid.UUID) (*models.OrderDTO, error) {
+	var order models.OrderDTO
+	var total, discount int64
+	q := `SELECT id::text,user_id::text,status,total,discount,created_at,updated_at
+	FROM orders
+		WHERE id=$1 AND ($2::uuid IS NULL OR user_id=$2);`
+	err := r.db.QueryRow(context.Background(), q, id, user).
+		Scan(&order.ID, &order.UserID, &order.Status, &total, &discount, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	order.Total = models.NewMoney(total, models.BaseCurrency)
+	order.Discount = models.NewMoney(discount, models.BaseCurrency)
+	orders := []models.OrderDTO{order}
+	if err := r.attachItems(orders); err != nil {
+		return nil, err
+	}
+	q = `SELECT coalesce(from_status,''),to_status,user_id,created_at
+	FROM order_histories
+		WHERE order_id=$1
+	ORDER BY created_at;`
+	rows, err := r.db.Query(context.Background(), q, id)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var h models.OrderHistory
+		if err := rows.Scan(&h.From, &h.To, &h.UserID, &h.CreatedAt); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		orders[0].History = append(orders[0].History, h)
+	}
+	return &orders[0], nil
+}
+
+//orders page by page from newest, user limits search to own orders
+func (r *Repository) GetOrders(f *models.OrderFilter, user *uuid.UUID) (*models.OrdersPage, error) {
+	page := models.OrdersPage{Orders: []models.OrderDTO{}}
+	limit := f.Limit
+	if limit <= 0 || limit > maxLimit {
+		limit = defaultLimit
+	}
+	w := &where{}
+	if user != nil {
+		w.add("user_id=$%d", *user)
+	} else if f.UserID != "" {
+		w.add("user_id=$%d", f.UserID)
+	}
+	if f.Status != "" {
+		w.add("status=$%d", f.Status)
+	}
+	if f.Cursor != "" {
+		c, err := decodeCursor(f.Cursor, "timestamptz")
+		if err != nil {
+			return nil, err
+		}
+		w.add("(created_at,id)<($%d::timestamptz,$%d::uuid)", c.Value, c.ID)
+	}
+	q := fmt.Sprintf(`SELECT id::text,user_id::text,status,total,discount,created_at,updated_at
+	FROM orders
+		WHERE %s
+	ORDER BY created_at DESC, id DESC
+	LIMIT %d;`, w, limit+1)
+	rows, err := r.db.Query(context.Background(), q, w.args...)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var order models.OrderDTO
+		var total, discount int64
+		if err := rows.Scan(&order.ID, &order.UserID, &order.Status, &total, &discount, &order.CreatedAt, &order.UpdatedAt); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		if len(page.Orders) == limit {
+			last := page.Orders[limit-1]
+			page.NextCursor = encodeCursor(cursor{Value: last.CreatedAt.Format(time.RFC3339Nano), ID: last.ID})
+			break
+		}
+		order.Total = models.NewMoney(total, models.BaseCurrency)
+		order.Discount = models.NewMoney(discount, models.BaseCurrency)
+		page.Orders = append(page.Orders, order)
+	}
+	rows.Close()
+	if err := r.attachItems(page.Orders); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (r *Repository) attachItems(orders []models.OrderDTO) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	ids := make([]string, len(orders))
+	index := make(map[string]int, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+		index[order.ID] = i
+	}
+	q := `SELECT order_id::text,product_id::text,coalesce(variant_id::text,''),name,options,price,quantity,weight,valume
+	FROM order_items
+		WHERE order_id=ANY($1::uuid[])
+	ORDER BY name, id;`
+	rows, err := r.db.Query(context.Background(), q, ids)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item models.OrderItemDTO
+		var order string
+		var price int64
+		if err := rows.Scan(&order, &item.ProductID, &item.VariantID, &item.Name, &item.Options, &price, &item.Quantity,
+			&item.Weight, &item.Valume); err != nil {
+			r.logger.Error(err)
+			return ErrInternal
+		}
+		item.Price = models.NewMoney(price, models.BaseCurrency)
+		i := index[order]
+		orders[i].Items = append(orders[i].Items, item)
+	}
+	return nil
+}
+
+//empty id is null
+func nullUUID(id string) interface{} {
+	if id == "" {
+		return nil
+	}
+	return id
+}
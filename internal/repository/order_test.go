@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/EMus88/Market/internal/models"
+	"github.com/go-playground/assert"
+	"github.com/gofrs/uuid"
+	"github.com/pashagolub/pgxmock"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_CreateOrder(t *testing.T) {
+	//init mock db connection
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mock.Close(context.Background())
+	r := NewRepository(mock, logrus.New())
+
+	//cart was checked out by concurrent request, one of lines is already removed
+	cart := &models.Cart{Lines: []models.CartLine{
+		{ID: "5e8a2d1c-7b3f-4a6e-9c8d-2f1e3a4b5c6d"},
+		{ID: "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21"},
+	}}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM cart_items").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectRollback()
+
+	_, err = r.CreateOrder(uuid.Must(uuid.NewV4()), cart)
+	assert.Equal(t, err, ErrCartChanged)
+	assert.Equal(t, mock.ExpectationsWereMet(), nil)
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const paymentColumns = `id::text,order_id::text,provider,intent_id,amount,status,coalesce(confirm_url,''),created_at`
+
+func (r *Repository) AddPayment(p *models.PaymentDTO) error {
+	q := `INSERT INTO payments(order_id,provider,intent_id,amount,status,confirm_url)
+ 		VALUES($1,$2,$3,$4,$5,$6)
+RETURNING id::text,created_at;`
+	err := r.db.QueryRow(context.Background(), q, p.OrderID, p.Provider, p.IntentID, p.Amount.Amount, p.Status, p.ConfirmURL).
+		Scan(&p.ID, &p.CreatedAt)
+	if err != nil {
+		if isPgError(err, "23505") {
+			return ErrAlreadyExist
+		}
+		if isPgError(err, "23503") {
+			return ErrNotFound
+		}
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//payment by id of intent in provider
+func (r *Repository) GetPayment(provider, intentID string) (*models.PaymentDTO, error) {
+	q := `SELECT ` + paymentColumns + `
+	FROM payments
+		WHERE provider=$1 AND intent_id=$2;`
+	return r.scanPayment(r.db.QueryRow(context.Background(), q, provider, intentID))
+}
+
+//last payment of order
+func (r *Repository) GetOrderPayment(orderID uuid.UUID) (*models.PaymentDTO, error) {
+	q := `SELECT ` + paymentColumns + `
+	FROM payments
+		WHERE order_id=$1
+	ORDER BY created_at DESC, id
+	LIMIT 1;`
+	return r.scanPayment(r.db.QueryRow(context.Background(), q, orderID))
+}
+
+func (r *Repository) scanPayment(row pgx.Row) (*models.PaymentDTO, error) {
+	var p models.PaymentDTO
+	var amount int64
+	err := row.Scan(&p.ID, &p.OrderID, &p.Provider, &p.IntentID, &amount, &p.Status, &p.ConfirmURL, &p.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	p.Amount = models.NewMoney(amount, models.BaseCurrency)
+	return &p, nil
+}
+
+//claim webhook event and apply its changes in one transaction, so event changes state only once,
+//false is returned for event processed already
+func (r *Repository) ApplyPaymentEvent(c *models.PaymentChange) (bool, error) {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.logger.Error(err)
+		return false, ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	var id string
+	q := `INSERT INTO payment_events(id,provider,type)
+ 		VALUES($1,$2,$3)
+	ON CONFLICT DO NOTHING
+RETURNING id;`
+	err = tx.QueryRow(ctx, q, c.Event.ID, c.Event.Provider, c.Event.Type).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.Error(err)
+		return false, ErrInternal
+	}
+	//payment which is in later status already is not changed
+	q = `UPDATE payments
+	SET status=$1, updated_at=now()
+		WHERE id=$2 AND status=ANY($3);`
+	if _, err := tx.Exec(ctx, q, c.Status, c.PaymentID, models.PaymentPrevious(c.Status)); err != nil {
+		r.logger.Error(err)
+		return false, ErrInternal
+	}
+	if c.OrderStatus != "" {
+		var status string
+		q = `SELECT status
+		FROM orders
+			WHERE id=$1
+		FOR UPDATE;`
+		if err := tx.QueryRow(ctx, q, c.OrderID).Scan(&status); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return false, ErrNotFound
+			}
+			r.logger.Error(err)
+			return false, ErrInternal
+		}
+		//order in this status already is not an error
+		if status != c.OrderStatus {
+			if !models.CanTransition(status, c.OrderStatus) {
+				return false, ErrStatusChanged
+			}
+			if err := r.changeOrderStatus(ctx, tx, c.OrderID, status, c.OrderStatus, nil); err != nil {
+				return false, err
+			}
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return false, ErrInternal
+	}
+	return true, nil
+}
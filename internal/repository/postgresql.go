@@ -59,10 +59,67 @@ func AutoMigration(isAllowed bool) error {
 		return err
 	}
 	//run automigration
-	if err := db.AutoMigrate(&models.User{}, &models.Product{}, &models.Category{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.Product{}, &models.Category{}, &models.ProductPrice{}, &models.ExchangeRate{}, &models.CategoryAttribute{}, &models.Variant{}, &models.Warehouse{}, &models.Stock{}, &models.StockMovement{}, &models.CartItem{}, &models.Order{}, &models.OrderItem{}, &models.OrderHistory{}, &models.Payment{}, &models.PaymentEvent{}, &models.Promo{}, &models.PromoUsage{}, &models.CartPromo{}, &models.PriceHistory{}, &models.ScheduledPrice{}, &models.ProductImage{}); err != nil {
 		return err
 	}
 
 	db.Exec("ALTER TABLE products ADD CONSTRAINT category_fk FOREIGN KEY (category_id) REFERENCES categories(id)")
+	db.Exec("ALTER TABLE categories ADD CONSTRAINT parent_fk FOREIGN KEY (parent_id) REFERENCES categories(id)")
+	db.Exec("ALTER TABLE product_prices ADD CONSTRAINT product_price_fk FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE")
+	db.Exec("ALTER TABLE category_attributes ADD CONSTRAINT category_attribute_fk FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE")
+	db.Exec("CREATE INDEX IF NOT EXISTS products_attributes ON products USING GIN(attributes)")
+	//each combination of options is one variant of product
+	db.Exec("ALTER TABLE variants ADD CONSTRAINT variant_product_fk FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE")
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS variants_product_options ON variants(product_id, options)")
+	//one stock of product or variant in warehouse
+	db.Exec("ALTER TABLE stocks ADD CONSTRAINT stock_warehouse_fk FOREIGN KEY (warehouse_id) REFERENCES warehouses(id)")
+	db.Exec("ALTER TABLE stocks ADD CONSTRAINT stock_product_fk FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE")
+	db.Exec("ALTER TABLE stocks ADD CONSTRAINT stock_variant_fk FOREIGN KEY (variant_id) REFERENCES variants(id) ON DELETE CASCADE")
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS stocks_key ON stocks(warehouse_id, product_id, coalesce(variant_id, '00000000-0000-0000-0000-000000000000'))")
+	//one line of product or variant in cart
+	db.Exec("ALTER TABLE cart_items ADD CONSTRAINT cart_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE")
+	db.Exec("ALTER TABLE cart_items ADD CONSTRAINT cart_product_fk FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE")
+	db.Exec("ALTER TABLE cart_items ADD CONSTRAINT cart_variant_fk FOREIGN KEY (variant_id) REFERENCES variants(id) ON DELETE CASCADE")
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS cart_items_key ON cart_items(user_id, product_id, coalesce(variant_id, '00000000-0000-0000-0000-000000000000'))")
+	//orders keep items and history after product is deleted
+	db.Exec("ALTER TABLE orders ADD CONSTRAINT order_user_fk FOREIGN KEY (user_id) REFERENCES users(id)")
+	db.Exec("ALTER TABLE order_items ADD CONSTRAINT order_item_fk FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE")
+	db.Exec("ALTER TABLE order_histories ADD CONSTRAINT order_history_fk FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE")
+	db.Exec("ALTER TABLE payments ADD CONSTRAINT payment_order_fk FOREIGN KEY (order_id) REFERENCES orders(id)")
+	//uses and entered codes are removed with promo
+	db.Exec("ALTER TABLE promo_usages ADD CONSTRAINT promo_usage_fk FOREIGN KEY (promo_id) REFERENCES promos(id) ON DELETE CASCADE")
+	db.Exec("ALTER TABLE promo_usages ADD CONSTRAINT promo_usage_order_fk FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE")
+	db.Exec("ALTER TABLE cart_promos ADD CONSTRAINT cart_promo_fk FOREIGN KEY (promo_id) REFERENCES promos(id) ON DELETE CASCADE")
+	db.Exec("ALTER TABLE cart_promos ADD CONSTRAINT cart_promo_user_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE")
+	db.Exec("ALTER TABLE price_histories ADD CONSTRAINT price_history_fk FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE")
+	db.Exec("ALTER TABLE scheduled_prices ADD CONSTRAINT scheduled_price_fk FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE")
+	//one primary image of product
+	db.Exec("ALTER TABLE product_images ADD CONSTRAINT product_image_fk FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE")
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS product_images_primary ON product_images(product_id) WHERE is_primary")
+	//stock ledger is append only
+	db.Exec("CREATE OR REPLACE RULE stock_movements_no_update AS ON UPDATE TO stock_movements DO INSTEAD NOTHING")
+	db.Exec("CREATE OR REPLACE RULE stock_movements_no_delete AS ON DELETE TO stock_movements DO INSTEAD NOTHING")
+	//indexes for keyset pagination of catalog
+	db.Exec("CREATE INDEX IF NOT EXISTS products_name_id ON products(name, id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS products_price_id ON products(price, id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS products_created_id ON products(created_at, id)")
+	//full text search by name and description with russian morphology
+	db.Exec(`ALTER TABLE products ADD COLUMN IF NOT EXISTS search tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('russian', coalesce(name, '')), 'A') ||
+			setweight(to_tsvector('russian', coalesce(description, '')), 'B')) STORED`)
+	db.Exec("CREATE INDEX IF NOT EXISTS products_search ON products USING GIN(search)")
+	//fuzzy search and autocomplete by trigrams
+	db.Exec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`)
+	db.Exec("CREATE INDEX IF NOT EXISTS products_name_trgm ON products USING GIN(lower(name) gin_trgm_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS categories_name_trgm ON categories USING GIN(lower(category) gin_trgm_ops)")
+	//category is visible only if all its ancestors are visible
+	db.Exec(`CREATE OR REPLACE RECURSIVE VIEW visible_categories(id, category, parent_id) AS
+		SELECT id, category, parent_id FROM categories
+			WHERE parent_id IS NULL AND visible=true
+		UNION ALL
+		SELECT c.id, c.category, c.parent_id FROM categories c
+			JOIN visible_categories v ON c.parent_id=v.id
+			WHERE c.visible=true`)
 	return nil
 }
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+//write change of product price to history, same price is not a change
+func (r *Repository) addPriceHistory(ctx context.Context, tx pgx.Tx, product uuid.UUID, old, new int64, user, schedule *uuid.UUID) error {
+	if old == new {
+		return nil
+	}
+	q := `INSERT INTO price_histories(product_id,old_price,new_price,user_id,schedule_id)
+ 		VALUES($1,$2,$3,$4,$5);`
+	if _, err := tx.Exec(ctx, q, product, old, new, user, schedule); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//price of product locked until end of transaction
+func (r *Repository) lockPrice(ctx context.Context, tx pgx.Tx, product uuid.UUID) (int64, error) {
+	var price int64
+	q := `SELECT price
+	FROM products
+		WHERE id=$1
+	FOR UPDATE;`
+	if err := tx.QueryRow(ctx, q, product).Scan(&price); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+		r.logger.Error(err)
+		return 0, ErrInternal
+	}
+	return price, nil
+}
+
+//changes of product price from newest
+func (r *Repository) GetPriceHistory(product uuid.UUID, f *models.PriceHistoryFilter) ([]models.PriceChange, error) {
+	changes := []models.PriceChange{}
+	limit := f.Limit
+	if limit <= 0 || limit > maxLimit {
+		limit = defaultLimit
+	}
+	w := &where{}
+	w.add("product_id=$%d", product)
+	if f.From != nil {
+		w.add("created_at>=$%d", *f.From)
+	}
+	if f.To != nil {
+		w.add("created_at<$%d", *f.To)
+	}
+	q := fmt.Sprintf(`SELECT id::text,old_price,new_price,coalesce(user_id::text,''),coalesce(schedule_id::text,''),created_at
+	FROM price_histories
+		WHERE %s
+	ORDER BY created_at DESC, id
+	LIMIT %d;`, w, limit)
+	rows, err := r.db.Query(context.Background(), q, w.args...)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var change models.PriceChange
+		var old, new int64
+		if err := rows.Scan(&change.ID, &old, &new, &change.UserID, &change.ScheduleID, &change.CreatedAt); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		change.OldPrice = models.NewMoney(old, models.BaseCurrency)
+		change.NewPrice = models.NewMoney(new, models.BaseCurrency)
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+//price of product at moment by history, current price is used when it was not changed since then
+func (r *Repository) GetPriceAt(product uuid.UUID, at time.Time) (*models.PriceAt, error) {
+	var price int64
+	q := `SELECT coalesce(
+		(SELECT new_price FROM price_histories WHERE product_id=$1 AND created_at<=$2 ORDER BY created_at DESC, id DESC LIMIT 1),
+		(SELECT old_price FROM price_histories WHERE product_id=$1 AND created_at>$2 ORDER BY created_at, id LIMIT 1),
+		price)
+	FROM products
+		WHERE id=$1;`
+	if err := r.db.QueryRow(context.Background(), q, product, at).Scan(&price); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	return &models.PriceAt{Price: models.NewMoney(price, models.BaseCurrency), At: at}, nil
+}
+
+func (r *Repository) AddScheduledPrice(product uuid.UUID, s *models.ScheduledPriceDTO, user *uuid.UUID) error {
+	q := `INSERT INTO scheduled_prices(product_id,price,starts_at,user_id)
+ 		VALUES($1,$2,$3,$4)
+RETURNING id::text,created_at;`
+	err := r.db.QueryRow(context.Background(), q, product, s.Price.Amount, s.StartsAt, user).Scan(&s.ID, &s.CreatedAt)
+	if err != nil {
+		if isPgError(err, "23503") {
+			return ErrNotFound
+		}
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if user != nil {
+		s.UserID = user.String()
+	}
+	return nil
+}
+
+//waiting prices by start time and then applied ones
+func (r *Repository) GetScheduledPrices(product uuid.UUID) ([]models.ScheduledPriceDTO, error) {
+	prices := []models.ScheduledPriceDTO{}
+	q := `SELECT id::text,price,starts_at,coalesce(user_id::text,''),applied_at,created_at
+	FROM scheduled_prices
+		WHERE product_id=$1
+	ORDER BY applied_at IS NOT NULL, starts_at DESC;`
+	rows, err := r.db.Query(context.Background(), q, product)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s models.ScheduledPriceDTO
+		var price int64
+		if err := rows.Scan(&s.ID, &price, &s.StartsAt, &s.UserID, &s.AppliedAt, &s.CreatedAt); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		s.Price = models.NewMoney(price, models.BaseCurrency)
+		prices = append(prices, s)
+	}
+	return prices, nil
+}
+
+//only waiting price can be cancelled
+func (r *Repository) DeleteScheduledPrice(product, id uuid.UUID) error {
+	q := `DELETE FROM scheduled_prices
+		WHERE id=$1 AND product_id=$2 AND applied_at IS NULL;`
+	tag, err := r.db.Exec(context.Background(), q, id, product)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+//set prices which start time has come, prices of one product are applied by start time
+func (r *Repository) ApplyScheduledPrices() (int, error) {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.logger.Error(err)
+		return 0, ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	var due []models.ScheduledPrice
+	q := `SELECT id,product_id,price,user_id
+	FROM scheduled_prices
+		WHERE applied_at IS NULL AND starts_at<=now()
+	ORDER BY starts_at, created_at
+	FOR UPDATE SKIP LOCKED;`
+	rows, err := tx.Query(ctx, q)
+	if err != nil {
+		r.logger.Error(err)
+		return 0, ErrInternal
+	}
+	for rows.Next() {
+		var s models.ScheduledPrice
+		if err := rows.Scan(&s.ID, &s.ProductID, &s.Price, &s.UserID); err != nil {
+			rows.Close()
+			r.logger.Error(err)
+			return 0, ErrInternal
+		}
+		due = append(due, s)
+	}
+	rows.Close()
+	for _, s := range due {
+		old, err := r.lockPrice(ctx, tx, s.ProductID)
+		if err != nil {
+			return 0, err
+		}
+		q := `UPDATE products
+		SET price=$1
+			WHERE id=$2;`
+		if _, err := tx.Exec(ctx, q, s.Price, s.ProductID); err != nil {
+			r.logger.Error(err)
+			return 0, ErrInternal
+		}
+		id := s.ID
+		if err := r.addPriceHistory(ctx, tx, s.ProductID, old, s.Price, s.UserID, &id); err != nil {
+			return 0, err
+		}
+		q = `UPDATE scheduled_prices
+		SET applied_at=now()
+			WHERE id=$1;`
+		if _, err := tx.Exec(ctx, q, s.ID); err != nil {
+			r.logger.Error(err)
+			return 0, ErrInternal
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return 0, ErrInternal
+	}
+	return len(due), nil
+}
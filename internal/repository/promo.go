@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const promoColumns = `p.id::text,coalesce(p.code,''),p.name,p.type,p.value,p.scope,%s,
+	p.min_total,p.starts_at,p.ends_at,p.usage_limit,p.per_user_limit,p.used,p.stackable,p.active`
+
+//promo can be used now
+const promoActive = `p.active AND (p.starts_at IS NULL OR p.starts_at<=now()) AND (p.ends_at IS NULL OR p.ends_at>now())
+		AND (p.usage_limit IS NULL OR p.used<p.usage_limit)`
+
+//user has not used promo as many times as allowed
+const promoUserLimit = `(p.per_user_limit IS NULL
+		OR (SELECT count(*) FROM promo_usages u WHERE u.promo_id=p.id AND u.user_id=$1)<p.per_user_limit)`
+
+func (r *Repository) AddPromo(p *models.PromoDTO) error {
+	if err := r.checkTargets(p); err != nil {
+		return err
+	}
+	code, value, minTotal := promoValues(p)
+	q := `INSERT INTO promos(code,name,type,value,scope,targets,min_total,starts_at,ends_at,usage_limit,per_user_limit,stackable,active)
+ 		VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
+RETURNING id::text;`
+	err := r.db.QueryRow(context.Background(), q, code, p.Name, p.Type, value, p.Scope, p.Targets, minTotal,
+		p.StartsAt, p.EndsAt, p.UsageLimit, p.PerUserLimit, p.Stackable, p.Active).Scan(&p.ID)
+	if err != nil {
+		if isPgError(err, "23505") {
+			return ErrAlreadyExist
+		}
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//replace rules of promo, count of uses is kept
+func (r *Repository) UpdatePromo(id uuid.UUID, p *models.PromoDTO) error {
+	if err := r.checkTargets(p); err != nil {
+		return err
+	}
+	code, value, minTotal := promoValues(p)
+	q := `UPDATE promos
+	SET code=$1,name=$2,type=$3,value=$4,scope=$5,targets=$6,min_total=$7,starts_at=$8,ends_at=$9,
+		usage_limit=$10,per_user_limit=$11,stackable=$12,active=$13
+		WHERE id=$14;`
+	tag, err := r.db.Exec(context.Background(), q, code, p.Name, p.Type, value, p.Scope, p.Targets, minTotal,
+		p.StartsAt, p.EndsAt, p.UsageLimit, p.PerUserLimit, p.Stackable, p.Active, id)
+	if err != nil {
+		if isPgError(err, "23505") {
+			return ErrAlreadyExist
+		}
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	p.ID = id.String()
+	return nil
+}
+
+func (r *Repository) DeletePromo(id uuid.UUID) error {
+	q := `DELETE FROM promos
+		WHERE id=$1;`
+	tag, err := r.db.Exec(context.Background(), q, id)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) GetPromo(id uuid.UUID) (*models.PromoDTO, error) {
+	q := `SELECT ` + fmt.Sprintf(promoColumns, "coalesce(p.targets,'{}')") + `
+	FROM promos p
+		WHERE p.id=$1;`
+	promo, err := scanPromo(r.db.QueryRow(context.Background(), q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	return promo, nil
+}
+
+func (r *Repository) GetPromos() ([]models.PromoDTO, error) {
+	q := `SELECT ` + fmt.Sprintf(promoColumns, "coalesce(p.targets,'{}')") + `
+	FROM promos p
+	ORDER BY p.created_at DESC, p.id;`
+	return r.queryPromos(q)
+}
+
+//promos which can be used now: automatic promos and promo code entered for cart of user,
+//targets of category promos have all subcategories
+func (r *Repository) GetActivePromos(user *uuid.UUID) ([]models.PromoDTO, error) {
+	q := `WITH RECURSIVE tree(root,category,id) AS (
+		SELECT category,category,id FROM categories
+		UNION ALL
+		SELECT tree.root,categories.category,categories.id
+		FROM categories
+		JOIN tree ON categories.parent_id=tree.id)
+	SELECT ` + fmt.Sprintf(promoColumns, `CASE WHEN p.scope='category'
+		THEN ARRAY(SELECT category::text FROM tree WHERE root=ANY(p.targets))
+		ELSE coalesce(p.targets,'{}') END`) + `
+	FROM promos p
+		WHERE ` + promoActive + `
+			AND (p.code IS NULL OR p.id=(SELECT promo_id FROM cart_promos WHERE user_id=$1))
+			AND ($1::uuid IS NULL OR ` + promoUserLimit + `)
+	ORDER BY p.created_at, p.id;`
+	return r.queryPromos(q, user)
+}
+
+func (r *Repository) queryPromos(q string, args ...interface{}) ([]models.PromoDTO, error) {
+	promos := []models.PromoDTO{}
+	rows, err := r.db.Query(context.Background(), q, args...)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		promo, err := scanPromo(rows)
+		if err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		promos = append(promos, *promo)
+	}
+	return promos, nil
+}
+
+func scanPromo(row pgx.Row) (*models.PromoDTO, error) {
+	var p models.PromoDTO
+	var value, minTotal int64
+	var active bool
+	err := row.Scan(&p.ID, &p.Code, &p.Name, &p.Type, &value, &p.Scope, &p.Targets, &minTotal,
+		&p.StartsAt, &p.EndsAt, &p.UsageLimit, &p.PerUserLimit, &p.Used, &p.Stackable, &active)
+	if err != nil {
+		return nil, err
+	}
+	p.Active = &active
+	if p.Type == models.DiscountPercent {
+		p.Percent = value
+	} else {
+		amount := models.NewMoney(value, models.BaseCurrency)
+		p.Amount = &amount
+	}
+	if minTotal > 0 {
+		min := models.NewMoney(minTotal, models.BaseCurrency)
+		p.MinTotal = &min
+	}
+	return &p, nil
+}
+
+//empty code is null, value is percent or amount
+func promoValues(p *models.PromoDTO) (interface{}, int64, int64) {
+	var code interface{}
+	if p.Code != "" {
+		code = p.Code
+	}
+	value := p.Percent
+	if p.Amount != nil {
+		value = p.Amount.Amount
+	}
+	var minTotal int64
+	if p.MinTotal != nil {
+		minTotal = p.MinTotal.Amount
+	}
+	return code, value, minTotal
+}
+
+//categories of category promo exist
+func (r *Repository) checkTargets(p *models.PromoDTO) error {
+	if p.Scope != models.ScopeCategory {
+		return nil
+	}
+	unique := make(map[string]bool, len(p.Targets))
+	for _, target := range p.Targets {
+		unique[target] = true
+	}
+	var count int
+	q := `SELECT count(*)
+	FROM categories
+		WHERE category=ANY($1);`
+	if err := r.db.QueryRow(context.Background(), q, p.Targets).Scan(&count); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if count != len(unique) {
+		return ErrNoCategory
+	}
+	return nil
+}
+
+//remember promo code for cart if it can be used by user
+func (r *Repository) SetCartPromo(user uuid.UUID, code string) error {
+	var id string
+	q := `INSERT INTO cart_promos(user_id,promo_id)
+	SELECT $1::uuid,p.id
+	FROM promos p
+		WHERE p.code=$2 AND ` + promoActive + ` AND ` + promoUserLimit + `
+	ON CONFLICT (user_id) DO UPDATE SET promo_id=EXCLUDED.promo_id
+RETURNING promo_id::text;`
+	if err := r.db.QueryRow(context.Background(), q, user, code).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+func (r *Repository) DeleteCartPromo(user uuid.UUID) error {
+	q := `DELETE FROM cart_promos
+		WHERE user_id=$1;`
+	if _, err := r.db.Exec(context.Background(), q, user); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//count use of promo by order, limits are checked under lock of promo
+func (r *Repository) usePromo(ctx context.Context, tx pgx.Tx, order, user uuid.UUID, d models.AppliedDiscount) error {
+	var perUser *int64
+	q := `UPDATE promos
+	SET used=used+1
+		WHERE id=$1 AND (usage_limit IS NULL OR used<usage_limit)
+	RETURNING per_user_limit;`
+	if err := tx.QueryRow(ctx, q, d.PromoID).Scan(&perUser); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPromoUsed
+		}
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if perUser != nil {
+		var used int64
+		q = `SELECT count(*)
+		FROM promo_usages
+			WHERE promo_id=$1 AND user_id=$2;`
+		if err := tx.QueryRow(ctx, q, d.PromoID, user).Scan(&used); err != nil {
+			r.logger.Error(err)
+			return ErrInternal
+		}
+		if used >= *perUser {
+			return ErrPromoUsed
+		}
+	}
+	q = `INSERT INTO promo_usages(promo_id,user_id,order_id,amount)
+ 		VALUES($1,$2,$3,$4);`
+	if _, err := tx.Exec(ctx, q, d.PromoID, user, order, d.Amount.Amount); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//uses of promos by cancelled order are not counted
+func (r *Repository) restorePromos(ctx context.Context, tx pgx.Tx, order uuid.UUID) error {
+	q := `UPDATE promos p
+	SET used=p.used-u.n
+	FROM (SELECT promo_id,count(*) AS n FROM promo_usages WHERE order_id=$1 GROUP BY promo_id) u
+		WHERE p.id=u.promo_id;`
+	if _, err := tx.Exec(ctx, q, order); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	q = `DELETE FROM promo_usages
+		WHERE order_id=$1;`
+	if _, err := tx.Exec(ctx, q, order); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
@@ -3,8 +3,15 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,28 +29,32 @@ func NewRepository(db DB, logger *logrus.Logger) *Repository {
 
 func (r *Repository) AddCategory(m *models.Category) error {
 	var id string
-	q := `INSERT INTO categories(category)
- 		VALUES($1)
+	q := `INSERT INTO categories(category,parent_id)
+ 		VALUES($1,$2)
 RETURNING id;`
-	row := r.db.QueryRow(context.Background(), q, m.Name).Scan(&id)
+	row := r.db.QueryRow(context.Background(), q, m.Name, m.ParentID).Scan(&id)
 	if id == "" {
 		r.logger.Error(row.Error())
+		if isPgError(row, "23503") {
+			return ErrNoCategory
+		}
 		return errors.New("error: internal db error")
 	}
 	return nil
 }
 
 func (r *Repository) AddProduct(m *models.ProductDTO) error {
-	var price uint64
 	var id string
-	q := `INSERT INTO products(name,weight,valume,description,photo,price,visible,category_id)
- 		VALUES($1,$2,$3,$4,$5,$6,$7,
+	attributes := m.Attributes
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+	q := `INSERT INTO products(name,weight,valume,description,photo,price,visible,attributes,options,category_id)
+ 		VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,
 		(SELECT id FROM categories
-			WHERE category=$8))
+			WHERE category=$10))
 RETURNING id;`
-	//convert price to uint64
-	price = uint64(m.Price * 100)
-	row := r.db.QueryRow(context.Background(), q, m.Name, m.Weight, m.Valume, m.Description, m.Photo, price, m.Visible, m.Category).Scan(&id)
+	row := r.db.QueryRow(context.Background(), q, m.Name, m.Weight, m.Valume, m.Description, m.Photo, m.Price.Amount, m.Visible, attributes, m.Options, m.Category).Scan(&id)
 	if id == "" {
 		r.logger.Error(row.Error())
 		return errors.New("error: internal db error")
@@ -64,80 +75,226 @@ func (r *Repository) ChangeVisible(v *models.Visible) error {
 	return nil
 }
 
-func (r *Repository) GetCatalog() ([]models.ProductDTO, error) {
-	var catalog []models.ProductDTO
-	q := `SELECT name,weight,valume,description,photo,price,category
+func (r *Repository) GetCatalog(f *models.CatalogFilter, all bool) (*models.CatalogPage, error) {
+	page := models.CatalogPage{Products: []models.ProductDTO{}}
+	sort, ok := catalogSorts[f.Sort]
+	if !ok {
+		sort = catalogSorts["name"]
+	}
+	limit := f.Limit
+	if limit <= 0 || limit > maxLimit {
+		limit = defaultLimit
+	}
+	var c *cursor
+	if f.Cursor != "" {
+		var err error
+		if c, err = decodeCursor(f.Cursor, sort.cast); err != nil {
+			return nil, err
+		}
+	}
+	w := &where{}
+	w.filter(&f.ProductFilter, all)
+	if !all {
+		w.add("products.visible=true")
+	}
+	//count all products matched by filter
+	q := fmt.Sprintf(`SELECT count(*)
 	FROM products
-	JOIN categories ON category_id=categories.id  
-	WHERE visible=true
-	ORDER BY name`
-	rows, err := r.db.Query(context.Background(), q)
+	JOIN %s categories ON category_id=categories.id
+	WHERE %s`, categoriesView(all), w)
+	if err := r.db.QueryRow(context.Background(), q, w.args...).Scan(&page.Total); err != nil {
+		r.logger.Error(err)
+		return nil, errors.New("error: internal db error")
+	}
+	//continue after last product of previous page
+	if c != nil {
+		op := ">"
+		if sort.desc {
+			op = "<"
+		}
+		w.add(fmt.Sprintf("(%s,products.id)%s($%%d::%s,$%%d::uuid)", sort.column, op, sort.cast), c.Value, c.ID)
+	}
+	order := "ASC"
+	if sort.desc {
+		order = "DESC"
+	}
+	//one extra row shows that next page exists
+	q = fmt.Sprintf(`SELECT products.id,name,weight,valume,description,photo,price,products.visible,category,attributes,options,created_at,%s
+	FROM products
+	JOIN %s categories ON category_id=categories.id
+	WHERE %s
+	ORDER BY %s %s, products.id %s
+	LIMIT %d`, availableColumn, categoriesView(all), w, sort.column, order, order, limit+1)
+	rows, err := r.db.Query(context.Background(), q, w.args...)
 	if err != nil {
 		r.logger.Error(err)
 		return nil, errors.New("error: internal db error")
 	}
+	defer rows.Close()
+	var last cursor
 	for rows.Next() {
 		var product models.ProductDTO
-		var price int
-		err := rows.Scan(&product.Name, &product.Weight, &product.Valume, &product.Description, &product.Photo, &price, &product.Category)
-		product.Price = float64(price) / 100
+		var price int64
+		var created time.Time
+		err := rows.Scan(&product.ID, &product.Name, &product.Weight, &product.Valume, &product.Description, &product.Photo, &price, &product.Visible, &product.Category, &product.Attributes, &product.Options, &created, &product.Available)
+		product.Price = models.NewMoney(price, models.BaseCurrency)
+		product.InStock = product.Available > 0
 		if err != nil {
 			r.logger.Error(err)
 			return nil, errors.New("error: internal db error")
 		}
-		catalog = append(catalog, product)
+		if len(page.Products) == limit {
+			page.NextCursor = encodeCursor(last)
+			break
+		}
+		page.Products = append(page.Products, product)
+		//remember sort value of product for cursor
+		last.ID = product.ID
+		switch sort.column {
+		case "name":
+			last.Value = product.Name
+		case "price":
+			last.Value = strconv.FormatInt(price, 10)
+		case "created_at":
+			last.Value = created.Format(time.RFC3339Nano)
+		}
+	}
+	rows.Close()
+	if err := r.attachVariants(page.Products, all); err != nil {
+		return nil, err
+	}
+	if err := r.attachImages(page.Products); err != nil {
+		return nil, err
 	}
 
-	return catalog, nil
+	return &page, nil
 }
 
-func (r *Repository) GetByCategory(productName string, category string) ([]models.ProductDTO, error) {
-	var result []models.ProductDTO
-	q := `SELECT name,weight,valume,description,photo,price
+//product by id, hidden products and variants are found if all is true
+func (r *Repository) GetProduct(id uuid.UUID, all bool) (*models.ProductDTO, error) {
+	var product models.ProductDTO
+	var price int64
+	q := `SELECT products.id,name,weight,valume,description,photo,price,products.visible,category,attributes,options,` + availableColumn + `
 	FROM products
-		WHERE name @@ $1 AND category_id=
-		(SELECT id FROM categories WHERE category=$2) AND visible=true;`
-	rows, err := r.db.Query(context.Background(), q, productName, category)
+	JOIN ` + categoriesView(all) + ` categories ON category_id=categories.id
+		WHERE products.id=$1 AND ($2 OR products.visible=true);`
+	err := r.db.QueryRow(context.Background(), q, id, all).
+		Scan(&product.ID, &product.Name, &product.Weight, &product.Valume, &product.Description, &product.Photo, &price, &product.Visible, &product.Category, &product.Attributes, &product.Options, &product.Available)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
 		r.logger.Error(err)
-		return nil, errors.New("error: internal db error")
+		return nil, ErrInternal
 	}
-	for rows.Next() {
-		var product models.ProductDTO
-		var price int
-		err := rows.Scan(&product.Name, &product.Weight, &product.Valume, &product.Description, &product.Photo, &price)
-		product.Price = float64(price / 100)
-		if err != nil {
-			r.logger.Error(err)
-			return nil, errors.New("error: internal db error")
-		}
-		result = append(result, product)
-
+	product.Price = models.NewMoney(price, models.BaseCurrency)
+	product.InStock = product.Available > 0
+	products := []models.ProductDTO{product}
+	if err := r.attachVariants(products, all); err != nil {
+		return nil, err
+	}
+	if err := r.attachImages(products); err != nil {
+		return nil, err
 	}
-	return result, nil
+
+	return &products[0], nil
 }
 
-func (r *Repository) GetByAllCategories(productName string) ([]models.ProductDTO, error) {
-	var result []models.ProductDTO
-	q := `SELECT name,weight,valume,description,photo,price
-	FROM products
-		WHERE name @@ $1 AND visible=true;`
-	rows, err := r.db.Query(context.Background(), q, productName)
+//change fields of product, change of price is written to price history with its author
+func (r *Repository) UpdateProduct(id uuid.UUID, m *models.ProductUpdate, user *uuid.UUID) error {
+	var set []string
+	var args []interface{}
+	//collect only changed fields
+	add := func(expr string, value interface{}) {
+		args = append(args, value)
+		set = append(set, fmt.Sprintf(expr, len(args)))
+	}
+	if m.Name != nil {
+		add("name=$%d", *m.Name)
+	}
+	if m.Weight != nil {
+		add("weight=$%d", *m.Weight)
+	}
+	if m.Valume != nil {
+		add("valume=$%d", *m.Valume)
+	}
+	if m.Description != nil {
+		add("description=$%d", *m.Description)
+	}
+	if m.Photo != nil {
+		add("photo=$%d", *m.Photo)
+	}
+	if m.Price != nil {
+		add("price=$%d", m.Price.Amount)
+	}
+	if m.Visible != nil {
+		add("visible=$%d", *m.Visible)
+	}
+	if m.Attributes != nil {
+		add("attributes=$%d", m.Attributes)
+	}
+	if m.Options != nil {
+		add("options=$%d", *m.Options)
+	}
+	if m.Category != nil {
+		add("category_id=(SELECT id FROM categories WHERE category=$%d)", *m.Category)
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		r.logger.Error(err)
-		return nil, errors.New("error: internal db error")
+		return ErrInternal
 	}
-	for rows.Next() {
-		var product models.ProductDTO
-		var price int
-		err := rows.Scan(&product.Name, &product.Weight, &product.Valume, &product.Description, &product.Photo, &price)
-		product.Price = float64(price / 100)
-		if err != nil {
-			r.logger.Error(err)
-			return nil, errors.New("error: internal db error")
+	defer tx.Rollback(ctx)
+	var old int64
+	if m.Price != nil {
+		if old, err = r.lockPrice(ctx, tx, id); err != nil {
+			return err
 		}
-		result = append(result, product)
+	}
+	args = append(args, id)
+	q := fmt.Sprintf(`UPDATE products
+	SET %s
+		WHERE id=$%d;`, strings.Join(set, ","), len(args))
+	tag, err := tx.Exec(ctx, q, args...)
+	if err != nil {
+		r.logger.Error(err)
+		if isPgError(err, "23505") {
+			return ErrAlreadyExist
+		}
+		if isPgError(err, "23502") {
+			return ErrNoCategory
+		}
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if m.Price != nil {
+		if err := r.addPriceHistory(ctx, tx, id, old, m.Price.Amount, user, nil); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
 
+func (r *Repository) DeleteProduct(id uuid.UUID) error {
+	q := `DELETE FROM products
+		WHERE id=$1;`
+	tag, err := r.db.Exec(context.Background(), q, id)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
 	}
-	return result, nil
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/spf13/viper"
+)
+
+//bounds of facets buckets if they are not set in config
+var defaultBounds = map[string][]string{
+	"price":  {"100", "500", "1000", "5000"},
+	"weight": {"0.5", "1", "5", "10"},
+	"valume": {"0.5", "1", "5", "10"},
+}
+
+//search queries use $1 as typed text and $2 as its cyrillic transliteration
+func searchFrom(all bool) string {
+	return fmt.Sprintf(`products
+	JOIN %s categories ON category_id=categories.id,
+	websearch_to_tsquery('russian', $1) query,
+	websearch_to_tsquery('russian', $2) alt`, categoriesView(all))
+}
+
+//full text match or similar name for typos, <% uses trigram index with threshold
+//pg_trgm.word_similarity_threshold which is set for transaction of search
+const searchMatch = `(search @@ query OR search @@ alt
+	OR lower($1) <%% lower(name)
+	OR $2 <%% lower(name))`
+
+//search runs its queries in transaction
+type querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+func searchSimilarity() float64 {
+	similarity := viper.GetFloat64("search.similarity")
+	if similarity <= 0 {
+		similarity = defaultSimilarity
+	}
+	return similarity
+}
+
+//columns of search result with rank and highlighted snippet
+const searchColumns = `products.id,name,weight,valume,description,photo,price,products.visible,category,attributes,options,
+	` + availableColumn + `,
+	(search @@ query OR search @@ alt) AS exact,
+	greatest(ts_rank(search, query), ts_rank(search, alt),
+		word_similarity(lower($1), lower(name)), word_similarity($2, lower(name))) AS rank,
+	CASE WHEN $1='' THEN '' ELSE ts_headline('russian', name || ' ' || coalesce(description, ''), query || alt,
+		'StartSel=<b>, StopSel=</b>, MaxFragments=2') END`
+
+//conditions of search with product filter
+func searchWhere(f models.SearchFilter, all bool) *where {
+	w := &where{args: []interface{}{f.Product, transliterate(f.Product)}}
+	if f.Product != "" {
+		w.add(searchMatch)
+	}
+	w.filter(&f.ProductFilter, all)
+	if !all {
+		w.add("products.visible=true")
+	}
+	return w
+}
+
+//search products with facets, each facet is counted without its own filter
+func (r *Repository) Search(f *models.SearchFilter, all bool) (*models.SearchResult, error) {
+	result := models.SearchResult{Products: []models.ProductDTO{}}
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	q := `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true);`
+	if _, err := tx.Exec(ctx, q, strconv.FormatFloat(searchSimilarity(), 'f', -1, 64)); err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	from := searchFrom(all)
+	w := searchWhere(*f, all)
+	q = fmt.Sprintf(`SELECT %s
+	FROM %s
+		WHERE %s
+	ORDER BY exact DESC, rank DESC, name;`, searchColumns, from, w)
+	rows, err := tx.Query(ctx, q, w.args...)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var product models.ProductDTO
+		var price int64
+		var exact bool
+		var rank float32
+		err := rows.Scan(&product.ID, &product.Name, &product.Weight, &product.Valume, &product.Description, &product.Photo, &price, &product.Visible, &product.Category, &product.Attributes, &product.Options, &product.Available, &exact, &rank, &product.Highlight)
+		if err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		product.Price = models.NewMoney(price, models.BaseCurrency)
+		product.InStock = product.Available > 0
+		result.Products = append(result.Products, product)
+	}
+	rows.Close()
+	if err := r.attachVariants(result.Products, all); err != nil {
+		return nil, err
+	}
+	if err := r.attachImages(result.Products); err != nil {
+		return nil, err
+	}
+
+	//categories
+	byCategory := *f
+	byCategory.Category = ""
+	if result.Facets.Categories, err = r.categoryFacet(tx, from, searchWhere(byCategory, all)); err != nil {
+		return nil, err
+	}
+	//price
+	byPrice := *f
+	byPrice.MinPrice, byPrice.MaxPrice = nil, nil
+	if result.Facets.Price, err = r.priceFacet(tx, from, searchWhere(byPrice, all)); err != nil {
+		return nil, err
+	}
+	//weight
+	byWeight := *f
+	byWeight.MinWeight, byWeight.MaxWeight = nil, nil
+	if result.Facets.Weight, err = r.rangeFacet(tx, from, searchWhere(byWeight, all), "weight", facetBounds("weight")); err != nil {
+		return nil, err
+	}
+	//valume
+	byValume := *f
+	byValume.MinValume, byValume.MaxValume = nil, nil
+	if result.Facets.Valume, err = r.rangeFacet(tx, from, searchWhere(byValume, all), "valume", facetBounds("valume")); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *Repository) categoryFacet(db querier, from string, w *where) ([]models.FacetCount, error) {
+	facets := []models.FacetCount{}
+	q := fmt.Sprintf(`SELECT category, count(*)
+	FROM %s
+		WHERE %s
+	GROUP BY category
+	ORDER BY count(*) DESC, category;`, from, w)
+	rows, err := db.Query(context.Background(), q, w.args...)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var facet models.FacetCount
+		if err := rows.Scan(&facet.Value, &facet.Count); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		facets = append(facets, facet)
+	}
+	return facets, nil
+}
+
+//count products in buckets between bounds
+func (r *Repository) rangeFacet(db querier, from string, w *where, column string, bounds []float64) ([]models.RangeFacet, error) {
+	facets := []models.RangeFacet{}
+	buckets, err := r.buckets(db, from, w, column, "float8", bounds)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range buckets {
+		//bucket 0 is below first bound, bucket len(bounds) is above last one
+		facet := models.RangeFacet{Count: b.count}
+		if b.bucket > 0 {
+			facet.From = &bounds[b.bucket-1]
+		}
+		if b.bucket < len(bounds) {
+			facet.To = &bounds[b.bucket]
+		}
+		facets = append(facets, facet)
+	}
+	return facets, nil
+}
+
+//count products in price buckets, bounds are set in base currency
+func (r *Repository) priceFacet(db querier, from string, w *where) ([]models.PriceFacet, error) {
+	facets := []models.PriceFacet{}
+	var bounds []models.Money
+	var thresholds []int64
+	for _, value := range facetValues("price") {
+		bound, err := models.ParseMoney(value, models.BaseCurrency)
+		if err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		bounds = append(bounds, bound)
+		thresholds = append(thresholds, bound.Amount)
+	}
+	buckets, err := r.buckets(db, from, w, "price", "bigint", thresholds)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range buckets {
+		facet := models.PriceFacet{Count: b.count}
+		if b.bucket > 0 {
+			facet.From = &bounds[b.bucket-1]
+		}
+		if b.bucket < len(bounds) {
+			facet.To = &bounds[b.bucket]
+		}
+		facets = append(facets, facet)
+	}
+	return facets, nil
+}
+
+type bucketCount struct {
+	bucket int
+	count  int
+}
+
+//count products by number of bucket between thresholds
+func (r *Repository) buckets(db querier, from string, w *where, column string, cast string, thresholds interface{}) ([]bucketCount, error) {
+	var buckets []bucketCount
+	args := append(append([]interface{}{}, w.args...), thresholds)
+	q := fmt.Sprintf(`SELECT width_bucket(%s, $%d::%s[]) AS bucket, count(*)
+	FROM %s
+		WHERE %s
+	GROUP BY bucket
+	ORDER BY bucket;`, column, len(args), cast, from, w)
+	rows, err := db.Query(context.Background(), q, args...)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var b bucketCount
+		if err := rows.Scan(&b.bucket, &b.count); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
+
+//read bounds of facet buckets from config, defaults are used if bounds are not set or not valid
+func facetValues(name string) []string {
+	values := viper.GetStringSlice("search.facets." + name)
+	if len(values) == 0 || !validBounds(name, values) {
+		return defaultBounds[name]
+	}
+	return values
+}
+
+//bounds are numbers (prices in base currency) in ascending order as width_bucket needs
+func validBounds(name string, values []string) bool {
+	previous := math.Inf(-1)
+	for _, value := range values {
+		var bound float64
+		if name == "price" {
+			price, err := models.ParseMoney(value, models.BaseCurrency)
+			if err != nil {
+				return false
+			}
+			bound = float64(price.Amount)
+		} else {
+			var err error
+			if bound, err = strconv.ParseFloat(value, 64); err != nil || math.IsNaN(bound) || math.IsInf(bound, 0) {
+				return false
+			}
+		}
+		if bound <= previous {
+			return false
+		}
+		previous = bound
+	}
+	return true
+}
+
+func facetBounds(name string) []float64 {
+	values := facetValues(name)
+	bounds := make([]float64, len(values))
+	for i, value := range values {
+		bounds[i], _ = strconv.ParseFloat(value, 64)
+	}
+	return bounds
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert"
+	"github.com/spf13/viper"
+)
+
+func Test_facetValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		facet  string
+		values []string
+		want   []string
+	}{
+		{name: "Not set", facet: "weight", want: defaultBounds["weight"]},
+		{name: "Ok", facet: "weight", values: []string{"0.2", "2"}, want: []string{"0.2", "2"}},
+		{name: "Not number", facet: "weight", values: []string{"0.2", "heavy"}, want: defaultBounds["weight"]},
+		{name: "Not ascending", facet: "valume", values: []string{"5", "1"}, want: defaultBounds["valume"]},
+		{name: "Price", facet: "price", values: []string{"99.90", "1000"}, want: []string{"99.90", "1000"}},
+		{name: "Not price", facet: "price", values: []string{"99.999"}, want: defaultBounds["price"]},
+	}
+	defer viper.Reset()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("search.facets."+tt.facet, tt.values)
+			assert.Equal(t, facetValues(tt.facet), tt.want)
+		})
+	}
+}
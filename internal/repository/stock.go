@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+//quantity of product itself in all warehouses, variants have their own quantity
+const availableColumn = `(SELECT coalesce(sum(quantity),0)::bigint FROM stocks WHERE product_id=products.id AND variant_id IS NULL) AS available`
+
+func (r *Repository) AddWarehouse(w *models.Warehouse) error {
+	q := `INSERT INTO warehouses(name,address)
+ 		VALUES($1,$2)
+RETURNING id;`
+	if err := r.db.QueryRow(context.Background(), q, w.Name, w.Address).Scan(&w.ID); err != nil {
+		r.logger.Error(err)
+		if isPgError(err, "23505") {
+			return ErrAlreadyExist
+		}
+		return ErrInternal
+	}
+	return nil
+}
+
+func (r *Repository) GetWarehouses() ([]models.Warehouse, error) {
+	warehouses := []models.Warehouse{}
+	q := `SELECT id,name,coalesce(address,'')
+	FROM warehouses
+	ORDER BY name;`
+	rows, err := r.db.Query(context.Background(), q)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var w models.Warehouse
+		if err := rows.Scan(&w.ID, &w.Name, &w.Address); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		warehouses = append(warehouses, w)
+	}
+	return warehouses, nil
+}
+
+//change stock and write movement to ledger in one transaction
+func (r *Repository) AdjustStock(a *models.StockAdjustment, user *uuid.UUID) (*models.StockMovement, error) {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer tx.Rollback(ctx)
+	movement := models.StockMovement{
+		WarehouseID: a.WarehouseID,
+		ProductID:   a.ProductID,
+		VariantID:   a.VariantID,
+		Delta:       a.Delta,
+		Reason:      a.Reason,
+		UserID:      user,
+	}
+	if err := r.moveStock(ctx, tx, &movement); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	return &movement, nil
+}
+
+//change stock by movement and write it to ledger inside transaction
+func (r *Repository) moveStock(ctx context.Context, tx pgx.Tx, m *models.StockMovement) error {
+	//variant must belong to product, quantity can't be negative by check constraint
+	q := `INSERT INTO stocks(warehouse_id,product_id,variant_id,quantity)
+	SELECT $1::uuid,$2::uuid,$3::uuid,$4::bigint
+		WHERE $3::uuid IS NULL OR EXISTS (SELECT 1 FROM variants WHERE id=$3 AND product_id=$2)
+	ON CONFLICT (warehouse_id,product_id,coalesce(variant_id,'00000000-0000-0000-0000-000000000000'))
+	DO UPDATE SET quantity=stocks.quantity+EXCLUDED.quantity
+RETURNING quantity;`
+	err := tx.QueryRow(ctx, q, m.WarehouseID, m.ProductID, m.VariantID, m.Delta).Scan(&m.Quantity)
+	if err != nil {
+		r.logger.Error(err)
+		if errors.Is(err, pgx.ErrNoRows) || isPgError(err, "23503") {
+			return ErrNotFound
+		}
+		if isPgError(err, "23514") {
+			return ErrNoStock
+		}
+		return ErrInternal
+	}
+	q = `INSERT INTO stock_movements(warehouse_id,product_id,variant_id,delta,quantity,reason,user_id,order_id)
+ 		VALUES($1,$2,$3,$4,$5,$6,$7,$8)
+RETURNING id,created_at;`
+	err = tx.QueryRow(ctx, q, m.WarehouseID, m.ProductID, m.VariantID, m.Delta, m.Quantity, m.Reason, m.UserID, m.OrderID).
+		Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+//stock of product and its variants by warehouses
+func (r *Repository) GetStock(productID uuid.UUID) ([]models.Stock, error) {
+	stocks := []models.Stock{}
+	q := `SELECT warehouse_id,product_id,variant_id,quantity
+	FROM stocks
+		WHERE product_id=$1
+	ORDER BY warehouse_id, variant_id NULLS FIRST;`
+	rows, err := r.db.Query(context.Background(), q, productID)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s models.Stock
+		if err := rows.Scan(&s.WarehouseID, &s.ProductID, &s.VariantID, &s.Quantity); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		stocks = append(stocks, s)
+	}
+	rows.Close()
+	//product without stocks is shown with empty list, unknown product is not found
+	if len(stocks) == 0 {
+		var exists bool
+		q = `SELECT EXISTS (SELECT 1 FROM products WHERE id=$1);`
+		if err := r.db.QueryRow(context.Background(), q, productID).Scan(&exists); err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		if !exists {
+			return nil, ErrNotFound
+		}
+	}
+	return stocks, nil
+}
+
+//last movements of stock ledger
+func (r *Repository) GetMovements(f *models.MovementFilter) ([]models.StockMovement, error) {
+	movements := []models.StockMovement{}
+	w := &where{}
+	if f.ProductID != "" {
+		w.add("product_id=$%d", f.ProductID)
+	}
+	if f.WarehouseID != "" {
+		w.add("warehouse_id=$%d", f.WarehouseID)
+	}
+	limit := f.Limit
+	if limit <= 0 || limit > maxLimit {
+		limit = defaultLimit
+	}
+	q := fmt.Sprintf(`SELECT id,warehouse_id,product_id,variant_id,delta,quantity,coalesce(reason,''),user_id,order_id,created_at
+	FROM stock_movements
+		WHERE %s
+	ORDER BY created_at DESC
+	LIMIT %d;`, w, limit)
+	rows, err := r.db.Query(context.Background(), q, w.args...)
+	if err != nil {
+		r.logger.Error(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m models.StockMovement
+		err := rows.Scan(&m.ID, &m.WarehouseID, &m.ProductID, &m.VariantID, &m.Delta, &m.Quantity, &m.Reason, &m.UserID, &m.OrderID, &m.CreatedAt)
+		if err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		movements = append(movements, m)
+	}
+	return movements, nil
+}
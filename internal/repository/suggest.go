@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultSimilarity   = 0.3
+	defaultSuggestLimit = 10
+)
+
+//names of products and categories which start with prefix
+func (r *Repository) Suggest(prefix string) (*models.Suggestions, error) {
+	limit := viper.GetInt("search.suggest_limit")
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+	suggestions := models.Suggestions{Products: []string{}, Categories: []string{}}
+	//name starts with prefix or one of its words does, beginning of name goes first
+	match := `lower(%[1]s) LIKE $1 || '%%' OR lower(%[1]s) LIKE '%% ' || $1 || '%%'
+		OR lower(%[1]s) LIKE $2 || '%%' OR lower(%[1]s) LIKE '%% ' || $2 || '%%'`
+	order := `lower(%[1]s) LIKE $1 || '%%' OR lower(%[1]s) LIKE $2 || '%%' DESC, length(%[1]s), %[1]s`
+	queries := []struct {
+		q      string
+		result *[]string
+	}{
+		{
+			q: fmt.Sprintf(`SELECT name FROM products
+				WHERE visible=true AND category_id IN (SELECT id FROM visible_categories)
+				AND (`+match+`)
+			ORDER BY `+order+`
+			LIMIT $3;`, "name"),
+			result: &suggestions.Products,
+		},
+		{
+			q: fmt.Sprintf(`SELECT category FROM visible_categories
+				WHERE `+match+`
+			ORDER BY `+order+`
+			LIMIT $3;`, "category"),
+			result: &suggestions.Categories,
+		},
+	}
+	escaped := likeEscape(prefix)
+	for _, query := range queries {
+		rows, err := r.db.Query(context.Background(), query.q, escaped, likeEscape(transliterate(prefix)), limit)
+		if err != nil {
+			r.logger.Error(err)
+			return nil, ErrInternal
+		}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				r.logger.Error(err)
+				return nil, ErrInternal
+			}
+			*query.result = append(*query.result, name)
+		}
+		rows.Close()
+	}
+	return &suggestions, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//latin letters combinations typed instead of cyrillic, longest first
+var translitTable = []struct {
+	latin    string
+	cyrillic string
+}{
+	{"shch", "щ"}, {"sch", "щ"},
+	{"zh", "ж"}, {"kh", "х"}, {"ts", "ц"}, {"ch", "ч"}, {"sh", "ш"},
+	{"yu", "ю"}, {"ju", "ю"}, {"ya", "я"}, {"ja", "я"}, {"yo", "ё"}, {"jo", "ё"},
+	{"a", "а"}, {"b", "б"}, {"v", "в"}, {"g", "г"}, {"d", "д"}, {"e", "е"},
+	{"z", "з"}, {"i", "и"}, {"y", "ы"}, {"j", "й"}, {"k", "к"}, {"l", "л"},
+	{"m", "м"}, {"n", "н"}, {"o", "о"}, {"p", "п"}, {"r", "р"}, {"s", "с"},
+	{"t", "т"}, {"u", "у"}, {"f", "ф"}, {"h", "х"}, {"c", "ц"}, {"w", "в"},
+	{"x", "кс"}, {"q", "к"},
+}
+
+//convert latin transliteration to cyrillic, "moloko" -> "молоко"
+func transliterate(s string) string {
+	s = strings.ToLower(s)
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] > unicode.MaxASCII {
+			//copy not latin symbol as is
+			_, size := utf8.DecodeRuneInString(s[i:])
+			b.WriteString(s[i : i+size])
+			i += size
+			continue
+		}
+		matched := false
+		for _, t := range translitTable {
+			if strings.HasPrefix(s[i:], t.latin) {
+				b.WriteString(t.cyrillic)
+				i += len(t.latin)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+//escape special symbols of LIKE pattern
+func likeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
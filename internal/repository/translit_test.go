@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert"
+)
+
+func Test_transliterate(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "Latin", in: "moloko", want: "молоко"},
+		{name: "Combinations", in: "Shchi Khleb Yabloko", want: "щи хлеб яблоко"},
+		{name: "Cyrillic", in: "Кефир 3.2%", want: "кефир 3.2%"},
+		{name: "Mixed", in: "сыр gauda", want: "сыр гауда"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, transliterate(tt.in), tt.want)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+)
+
+func (r *Repository) AddVariant(productID uuid.UUID, v *models.VariantDTO) error {
+	q := `INSERT INTO variants(product_id,options,weight,valume,price,barcode,visible)
+ 		VALUES($1,$2,$3,$4,$5,$6,$7)
+RETURNING id;`
+	err := r.db.QueryRow(context.Background(), q, productID, v.Options, v.Weight, v.Valume, v.Price.Amount, barcode(v.Barcode), v.Visible).Scan(&v.ID)
+	if err != nil {
+		r.logger.Error(err)
+		if isPgError(err, "23505") {
+			return ErrAlreadyExist
+		}
+		if isPgError(err, "23503") {
+			return ErrNotFound
+		}
+		return ErrInternal
+	}
+	return nil
+}
+
+func (r *Repository) UpdateVariant(productID, id uuid.UUID, m *models.VariantUpdate) error {
+	var set []string
+	var args []interface{}
+	//collect only changed fields
+	add := func(expr string, value interface{}) {
+		args = append(args, value)
+		set = append(set, fmt.Sprintf(expr, len(args)))
+	}
+	if m.Options != nil {
+		add("options=$%d", m.Options)
+	}
+	if m.Weight != nil {
+		add("weight=$%d", *m.Weight)
+	}
+	if m.Valume != nil {
+		add("valume=$%d", *m.Valume)
+	}
+	if m.Price != nil {
+		add("price=$%d", m.Price.Amount)
+	}
+	if m.Barcode != nil {
+		add("barcode=$%d", barcode(*m.Barcode))
+	}
+	if m.Visible != nil {
+		add("visible=$%d", *m.Visible)
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	args = append(args, id, productID)
+	q := fmt.Sprintf(`UPDATE variants
+	SET %s
+		WHERE id=$%d AND product_id=$%d;`, strings.Join(set, ","), len(args)-1, len(args))
+	tag, err := r.db.Exec(context.Background(), q, args...)
+	if err != nil {
+		r.logger.Error(err)
+		if isPgError(err, "23505") {
+			return ErrAlreadyExist
+		}
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) DeleteVariant(productID, id uuid.UUID) error {
+	q := `DELETE FROM variants
+		WHERE id=$1 AND product_id=$2;`
+	tag, err := r.db.Exec(context.Background(), q, id, productID)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+//embed variants into products, hidden variants only for administrator
+func (r *Repository) attachVariants(products []models.ProductDTO, all bool) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]string, len(products))
+	index := make(map[string]int, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+		index[product.ID] = i
+	}
+	q := `SELECT id::text,product_id::text,options,weight,valume,price,barcode,visible,
+		(SELECT coalesce(sum(quantity),0)::bigint FROM stocks WHERE variant_id=variants.id)
+	FROM variants
+		WHERE product_id=ANY($1::uuid[]) AND ($2 OR visible=true)
+	ORDER BY price, id;`
+	rows, err := r.db.Query(context.Background(), q, ids, all)
+	if err != nil {
+		r.logger.Error(err)
+		return ErrInternal
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var v models.VariantDTO
+		var productID string
+		var price int64
+		var code *string
+		var visible bool
+		if err := rows.Scan(&v.ID, &productID, &v.Options, &v.Weight, &v.Valume, &price, &code, &visible, &v.Available); err != nil {
+			r.logger.Error(err)
+			return ErrInternal
+		}
+		v.Price = models.NewMoney(price, models.BaseCurrency)
+		v.Visible = &visible
+		v.InStock = v.Available > 0
+		if code != nil {
+			v.Barcode = *code
+		}
+		i := index[productID]
+		products[i].Variants = append(products[i].Variants, v)
+		//product is in stock when any of its variants is
+		products[i].InStock = products[i].InStock || v.InStock
+	}
+	return nil
+}
+
+//empty barcode is stored as null, so many variants can be without barcode
+func barcode(code string) *string {
+	if code == "" {
+		return nil
+	}
+	return &code
+}
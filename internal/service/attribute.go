@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+)
+
+var ErrAttributes = errors.New("error: invalid attributes")
+
+//check values of product attributes by schema of its category
+func ValidateAttributes(schema []models.CategoryAttribute, values map[string]interface{}) error {
+	types := make(map[string]string, len(schema))
+	for _, a := range schema {
+		types[a.Name] = a.Type
+		if _, ok := values[a.Name]; a.Required && !ok {
+			return fmt.Errorf("%w: %s is required", ErrAttributes, a.Name)
+		}
+	}
+	for name, value := range values {
+		t, ok := types[name]
+		if !ok {
+			return fmt.Errorf("%w: %s is unknown", ErrAttributes, name)
+		}
+		valid := false
+		switch value.(type) {
+		case float64:
+			valid = t == "number"
+		case string:
+			valid = t == "string"
+		case bool:
+			valid = t == "bool"
+		}
+		if !valid {
+			return fmt.Errorf("%w: %s must be %s", ErrAttributes, name, t)
+		}
+	}
+	return nil
+}
+
+//check attributes of new product
+func (s *Service) CheckAttributes(category string, values map[string]interface{}) error {
+	schema, err := s.Repository.GetAttributesByName(category)
+	if err != nil {
+		return err
+	}
+	return ValidateAttributes(schema, values)
+}
+
+//check attributes of product after update, attributes of another category are checked too,
+//options can't be changed while product has variants
+func (s *Service) CheckProductUpdate(id uuid.UUID, m *models.ProductUpdate) error {
+	if m.Attributes == nil && m.Category == nil && m.Options == nil {
+		return nil
+	}
+	product, err := s.Repository.GetProduct(id, true)
+	if err != nil {
+		return err
+	}
+	if m.Options != nil && len(product.Variants) > 0 && !sameOptions(product.Options, *m.Options) {
+		return fmt.Errorf("%w: product has variants", ErrOptions)
+	}
+	if m.Attributes == nil && m.Category == nil {
+		return nil
+	}
+	if m.Category != nil {
+		product.Category = *m.Category
+	}
+	if m.Attributes != nil {
+		product.Attributes = m.Attributes
+	}
+	return s.CheckAttributes(product.Category, product.Attributes)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/go-playground/assert"
+)
+
+func Test_ValidateAttributes(t *testing.T) {
+	schema := []models.CategoryAttribute{
+		{Name: "screen", Type: "number", Unit: "inch", Required: true},
+		{Name: "color", Type: "string"},
+		{Name: "smart", Type: "bool"},
+	}
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		valid  bool
+	}{
+		{name: "all", values: map[string]interface{}{"screen": 55.0, "color": "black", "smart": true}, valid: true},
+		{name: "only required", values: map[string]interface{}{"screen": 32.0}, valid: true},
+		{name: "missing required", values: map[string]interface{}{"color": "black"}},
+		{name: "unknown", values: map[string]interface{}{"screen": 55.0, "weight": 10.0}},
+		{name: "number as string", values: map[string]interface{}{"screen": "55"}},
+		{name: "bool as number", values: map[string]interface{}{"screen": 55.0, "smart": 1.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAttributes(schema, tt.values)
+			assert.Equal(t, err == nil, tt.valid)
+			assert.Equal(t, errors.Is(err, ErrAttributes), !tt.valid)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+)
+
+//cart with warnings checked on every reading, so hidden and sold out products are not bought,
+//automatic promos and promo code of user are applied
+func (s *Service) GetCart(user uuid.UUID) (*models.Cart, error) {
+	lines, err := s.Repository.GetCartLines(user)
+	if err != nil {
+		return nil, err
+	}
+	cart := NewCart(lines)
+	promos, err := s.Repository.GetActivePromos(&user)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range promos {
+		if p.Code != "" {
+			cart.PromoCode = p.Code
+		}
+	}
+	ApplyPromos(cart, promos)
+	return cart, nil
+}
+
+//check lines and count total of lines which can be bought
+func NewCart(lines []models.CartLine) *models.Cart {
+	cart := models.Cart{Lines: lines, Valid: true}
+	var total int64
+	for i := range cart.Lines {
+		line := &cart.Lines[i]
+		line.Warnings = nil
+		line.Sum = models.NewMoney(line.Price.Amount*line.Quantity, line.Price.Currency)
+		line.Discount = models.NewMoney(0, line.Price.Currency)
+		blocked := true
+		switch {
+		case !line.Visible:
+			line.Warnings = append(line.Warnings, models.WarningHidden)
+		case line.Available <= 0:
+			line.Warnings = append(line.Warnings, models.WarningOutOfStock)
+		case line.Available < line.Quantity:
+			line.Warnings = append(line.Warnings, models.WarningNotEnough)
+		default:
+			blocked = false
+		}
+		//price change doesn't block buying, user is only informed
+		if line.AddedPrice.Amount != line.Price.Amount {
+			line.Warnings = append(line.Warnings, models.WarningPriceChanged)
+		}
+		if blocked {
+			cart.Valid = false
+			continue
+		}
+		total += line.Sum.Amount
+	}
+	cart.Subtotal = models.NewMoney(total, models.BaseCurrency)
+	cart.Discount = models.NewMoney(0, models.BaseCurrency)
+	cart.Total = cart.Subtotal
+	return &cart
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/go-playground/assert"
+)
+
+func Test_NewCart(t *testing.T) {
+	rub := func(amount int64) models.Money { return models.NewMoney(amount, models.BaseCurrency) }
+	tests := []struct {
+		name     string
+		line     models.CartLine
+		warnings []string
+		total    int64
+		valid    bool
+	}{
+		{
+			name:  "Ok",
+			line:  models.CartLine{Price: rub(8990), AddedPrice: rub(8990), Quantity: 2, Available: 5, Visible: true},
+			total: 17980,
+			valid: true,
+		},
+		{
+			name:     "Price changed",
+			line:     models.CartLine{Price: rub(9990), AddedPrice: rub(8990), Quantity: 1, Available: 5, Visible: true},
+			warnings: []string{models.WarningPriceChanged},
+			total:    9990,
+			valid:    true,
+		},
+		{
+			name:     "Hidden",
+			line:     models.CartLine{Price: rub(8990), AddedPrice: rub(8990), Quantity: 1, Available: 5},
+			warnings: []string{models.WarningHidden},
+		},
+		{
+			name:     "Out of stock",
+			line:     models.CartLine{Price: rub(8990), AddedPrice: rub(8990), Quantity: 1, Visible: true},
+			warnings: []string{models.WarningOutOfStock},
+		},
+		{
+			name:     "Not enough",
+			line:     models.CartLine{Price: rub(8990), AddedPrice: rub(7990), Quantity: 3, Available: 2, Visible: true},
+			warnings: []string{models.WarningNotEnough, models.WarningPriceChanged},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cart := NewCart([]models.CartLine{tt.line})
+			assert.Equal(t, cart.Lines[0].Warnings, tt.warnings)
+			assert.Equal(t, cart.Total.Amount, tt.total)
+			assert.Equal(t, cart.Valid, tt.valid)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"github.com/EMus88/Market/internal/models"
+)
+
+//discounts of one combination of promos
+type discounts struct {
+	lines   []int64
+	applied []models.AppliedDiscount
+	total   int64
+}
+
+//apply promos to lines which can be bought, every not stackable promo is tried alone
+//and all stackable promos together, the biggest discount is used
+func ApplyPromos(cart *models.Cart, promos []models.PromoDTO) {
+	var stackable []models.PromoDTO
+	var best *discounts
+	try := func(promos []models.PromoDTO) {
+		d := discountCart(cart, promos)
+		if d.total > 0 && (best == nil || d.total > best.total) {
+			best = d
+		}
+	}
+	for _, p := range promos {
+		if p.MinTotal != nil && cart.Subtotal.Amount < p.MinTotal.Amount {
+			continue
+		}
+		if p.Stackable {
+			stackable = append(stackable, p)
+			continue
+		}
+		try([]models.PromoDTO{p})
+	}
+	try(stackable)
+	if best == nil {
+		return
+	}
+	for i := range cart.Lines {
+		cart.Lines[i].Discount = models.NewMoney(best.lines[i], cart.Lines[i].Price.Currency)
+	}
+	cart.Discounts = best.applied
+	cart.Discount = models.NewMoney(best.total, models.BaseCurrency)
+	cart.Total = models.NewMoney(cart.Subtotal.Amount-best.total, models.BaseCurrency)
+}
+
+//product and category discounts go first, cart discounts are taken from the rest
+func discountCart(cart *models.Cart, promos []models.PromoDTO) *discounts {
+	d := discounts{lines: make([]int64, len(cart.Lines))}
+	rest := make([]int64, len(cart.Lines))
+	for i, line := range cart.Lines {
+		if buyable(line) {
+			rest[i] = line.Sum.Amount
+		}
+	}
+	add := func(p models.PromoDTO, amount int64) {
+		if amount > 0 {
+			d.applied = append(d.applied, models.AppliedDiscount{
+				PromoID: p.ID,
+				Code:    p.Code,
+				Name:    p.Name,
+				Amount:  models.NewMoney(amount, models.BaseCurrency),
+			})
+			d.total += amount
+		}
+	}
+	for _, p := range promos {
+		if p.Scope == models.ScopeCart {
+			continue
+		}
+		var amount int64
+		for i, line := range cart.Lines {
+			if rest[i] == 0 || !targeted(p, line) {
+				continue
+			}
+			var off int64
+			if p.Type == models.DiscountPercent {
+				off = rest[i] * p.Percent / 100
+			} else if p.Amount != nil {
+				off = p.Amount.Amount * line.Quantity
+			}
+			if off > rest[i] {
+				off = rest[i]
+			}
+			rest[i] -= off
+			d.lines[i] += off
+			amount += off
+		}
+		add(p, amount)
+	}
+	for _, p := range promos {
+		if p.Scope != models.ScopeCart {
+			continue
+		}
+		var left int64
+		for _, r := range rest {
+			left += r
+		}
+		var off int64
+		if p.Type == models.DiscountPercent {
+			off = left * p.Percent / 100
+		} else if p.Amount != nil {
+			off = p.Amount.Amount
+		}
+		if off > left {
+			off = left
+		}
+		add(p, off)
+		//next cart discount is taken from what is left
+		spread(rest, off)
+	}
+	return &d
+}
+
+//take amount from rests of lines one by one
+func spread(rest []int64, amount int64) {
+	for i := range rest {
+		if amount == 0 {
+			return
+		}
+		take := rest[i]
+		if take > amount {
+			take = amount
+		}
+		rest[i] -= take
+		amount -= take
+	}
+}
+
+//promo is for product or category of line, targets of category promo have subcategories too
+func targeted(p models.PromoDTO, line models.CartLine) bool {
+	for _, target := range p.Targets {
+		if (p.Scope == models.ScopeProduct && target == line.ProductID) ||
+			(p.Scope == models.ScopeCategory && target == line.Category) {
+			return true
+		}
+	}
+	return false
+}
+
+//line is counted in total of cart
+func buyable(line models.CartLine) bool {
+	return line.Visible && line.Available >= line.Quantity
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/go-playground/assert"
+)
+
+func Test_ApplyPromos(t *testing.T) {
+	rub := func(amount int64) *models.Money {
+		m := models.NewMoney(amount, models.BaseCurrency)
+		return &m
+	}
+	lines := func() []models.CartLine {
+		return []models.CartLine{
+			{ProductID: "milk", Category: "dairy", Price: *rub(10000), AddedPrice: *rub(10000), Quantity: 2, Available: 5, Visible: true},
+			{ProductID: "bread", Category: "bakery", Price: *rub(5000), AddedPrice: *rub(5000), Quantity: 1, Available: 5, Visible: true},
+			//hidden line is not discounted
+			{ProductID: "cheese", Category: "dairy", Price: *rub(30000), AddedPrice: *rub(30000), Quantity: 1, Available: 5},
+		}
+	}
+	dairy := models.PromoDTO{Name: "dairy", Type: models.DiscountPercent, Percent: 10, Scope: models.ScopeCategory, Targets: []string{"dairy"}}
+	bread := models.PromoDTO{Name: "bread", Type: models.DiscountFixed, Amount: rub(1000), Scope: models.ScopeProduct, Targets: []string{"bread"}}
+	cart := models.PromoDTO{Name: "cart", Code: "SALE", Type: models.DiscountFixed, Amount: rub(3000), Scope: models.ScopeCart}
+	tests := []struct {
+		name     string
+		promos   []models.PromoDTO
+		discount int64
+		applied  []string
+	}{
+		{
+			name:   "No promos",
+			promos: nil,
+		},
+		{
+			name:     "Category percent",
+			promos:   []models.PromoDTO{dairy},
+			discount: 2000,
+			applied:  []string{"dairy"},
+		},
+		{
+			name:     "Best of not stackable",
+			promos:   []models.PromoDTO{dairy, bread, cart},
+			discount: 3000,
+			applied:  []string{"cart"},
+		},
+		{
+			name:     "Stackable are summed",
+			promos:   []models.PromoDTO{stack(dairy), stack(bread), stack(cart)},
+			discount: 6000,
+			applied:  []string{"dairy", "bread", "cart"},
+		},
+		{
+			name:     "Not stackable is better than stack",
+			promos:   []models.PromoDTO{stack(dairy), stack(bread), cart},
+			discount: 3000,
+			applied:  []string{"cart"},
+		},
+		{
+			name:   "Min total not reached",
+			promos: []models.PromoDTO{{Name: "big", Type: models.DiscountPercent, Percent: 50, Scope: models.ScopeCart, MinTotal: rub(30000)}},
+		},
+		{
+			name:     "Fixed is not bigger than total",
+			promos:   []models.PromoDTO{{Name: "gift", Type: models.DiscountFixed, Amount: rub(100000), Scope: models.ScopeCart}},
+			discount: 25000,
+			applied:  []string{"gift"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCart(lines())
+			ApplyPromos(c, tt.promos)
+			var applied []string
+			for _, d := range c.Discounts {
+				applied = append(applied, d.Name)
+			}
+			assert.Equal(t, c.Subtotal.Amount, int64(25000))
+			assert.Equal(t, c.Discount.Amount, tt.discount)
+			assert.Equal(t, c.Total.Amount, 25000-tt.discount)
+			assert.Equal(t, applied, tt.applied)
+		})
+	}
+}
+
+func stack(p models.PromoDTO) models.PromoDTO {
+	p.Stackable = true
+	return p
+}
+
+func Test_ValidatePromo(t *testing.T) {
+	amount := models.NewMoney(1000, models.BaseCurrency)
+	inactive := false
+	tests := []struct {
+		name   string
+		promo  models.PromoDTO
+		ok     bool
+		active bool
+	}{
+		{name: "Ok", promo: models.PromoDTO{Type: models.DiscountPercent, Percent: 10, Scope: models.ScopeCart}, ok: true, active: true},
+		{name: "Inactive", promo: models.PromoDTO{Type: models.DiscountPercent, Percent: 10, Scope: models.ScopeCart, Active: &inactive}, ok: true},
+		{name: "Bad percent", promo: models.PromoDTO{Type: models.DiscountPercent, Percent: 120, Scope: models.ScopeCart}},
+		{name: "No amount", promo: models.PromoDTO{Type: models.DiscountFixed, Scope: models.ScopeCart}},
+		{name: "No targets", promo: models.PromoDTO{Type: models.DiscountFixed, Amount: &amount, Scope: models.ScopeCategory}},
+		{name: "Bad product", promo: models.PromoDTO{Type: models.DiscountFixed, Amount: &amount, Scope: models.ScopeProduct, Targets: []string{"milk"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePromo(&tt.promo)
+			assert.Equal(t, err == nil, tt.ok)
+			if tt.ok {
+				assert.Equal(t, *tt.promo.Active, tt.active)
+			}
+		})
+	}
+}
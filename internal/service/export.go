@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/spreadsheet"
+)
+
+//format of export with json of one product on every line
+const FormatJSONL = "jsonl"
+
+var ErrExportFormat = errors.New("error: unknown export format, must be csv, xlsx or jsonl")
+
+//columns of exported table before attributes, every variant has its own row after row of its product
+var exportColumns = []string{"id", "variant_id", "name", "category", "price", "weight", "valume", "description", "photo", "images", "visible", "options", "barcode", "available"}
+
+//hidden products are exported with "visible":false
+type exportedProduct struct {
+	*models.ProductDTO
+	Visible bool `json:"visible"`
+}
+
+func ExportFormat(format string) error {
+	switch format {
+	case spreadsheet.FormatCSV, spreadsheet.FormatXLSX, FormatJSONL:
+		return nil
+	}
+	return ErrExportFormat
+}
+
+func ExportContentType(format string) string {
+	if format == FormatJSONL {
+		return "application/x-ndjson"
+	}
+	return spreadsheet.ContentType(format)
+}
+
+//write all products including hidden ones to w, products are streamed from db
+//and are not collected in memory
+func (s *Service) ExportProducts(ctx context.Context, w io.Writer, format string) error {
+	if err := ExportFormat(format); err != nil {
+		return err
+	}
+	if format == FormatJSONL {
+		buf := bufio.NewWriter(w)
+		enc := json.NewEncoder(buf)
+		err := s.Repository.ExportProducts(ctx, true, func(product *models.ProductDTO) error {
+			return enc.Encode(exportedProduct{ProductDTO: product, Visible: product.Visible})
+		})
+		if err != nil {
+			return err
+		}
+		return buf.Flush()
+	}
+
+	//attributes of all products are columns, so they are known before first row
+	names, err := s.Repository.GetAttributeNames()
+	if err != nil {
+		return err
+	}
+	writer, err := spreadsheet.NewWriter(w, format)
+	if err != nil {
+		return err
+	}
+	header := make([]interface{}, 0, len(exportColumns)+len(names))
+	for _, column := range exportColumns {
+		header = append(header, column)
+	}
+	for _, name := range names {
+		header = append(header, attributePrefix+name)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	err = s.Repository.ExportProducts(ctx, true, func(product *models.ProductDTO) error {
+		if err := writer.Write(productCells(product, names)); err != nil {
+			return err
+		}
+		for i := range product.Variants {
+			if err := writer.Write(variantCells(product, &product.Variants[i], len(names))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func productCells(product *models.ProductDTO, names []string) []interface{} {
+	images := make([]string, len(product.Images))
+	for i, img := range product.Images {
+		images[i] = img.URL
+	}
+	cells := []interface{}{
+		product.ID,
+		nil,
+		product.Name,
+		product.Category,
+		spreadsheet.Number(product.Price.String()),
+		product.Weight,
+		product.Valume,
+		product.Description,
+		strings.Join(product.Photo, "|"),
+		strings.Join(images, "|"),
+		product.Visible,
+		strings.Join(product.Options, ","),
+		nil,
+		product.Available,
+	}
+	for _, name := range names {
+		value, ok := product.Attributes[name]
+		if !ok {
+			cells = append(cells, nil)
+			continue
+		}
+		cells = append(cells, value)
+	}
+	return cells
+}
+
+//row of variant has product id, name and category to be found by filters of spreadsheet,
+//attributes are empty as they are set for product
+func variantCells(product *models.ProductDTO, v *models.VariantDTO, attributes int) []interface{} {
+	cells := []interface{}{
+		product.ID,
+		v.ID,
+		product.Name,
+		product.Category,
+		spreadsheet.Number(v.Price.String()),
+		v.Weight,
+		v.Valume,
+		nil,
+		nil,
+		nil,
+		v.Visible != nil && *v.Visible,
+		variantOptions(product.Options, v.Options),
+		v.Barcode,
+		v.Available,
+	}
+	return append(cells, make([]interface{}, attributes)...)
+}
+
+//values of options in order of product options, like "color=red,size=M"
+func variantOptions(names []string, options map[string]string) string {
+	pairs := make([]string, 0, len(options))
+	for _, name := range names {
+		if value, ok := options[name]; ok {
+			pairs = append(pairs, name+"="+value)
+		}
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/EMus88/Market/internal/repository"
+
+	"github.com/go-playground/assert"
+	"github.com/pashagolub/pgxmock"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_ExportProducts(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		file   string
+		err    error
+	}{
+		{
+			name:   "Csv",
+			format: "csv",
+			file: "\xef\xbb\xbfid;variant_id;name;category;price;weight;valume;description;photo;images;visible;options;barcode;available;attr:fat;attr:volume\n" +
+				"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21;;milk;food;89.90;1;1;fresh;;;false;pack;;7;3.2;\n" +
+				"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21;3d2c1b0a-9f8e-4d7c-8b6a-5f4e3d2c1b0a;milk;food;99.90;1.1;1;;;;true;pack=big;4601234567890;7;;\n",
+		},
+		{
+			name:   "Json lines",
+			format: "jsonl",
+			file: `{"id":"0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21","name":"milk","weight":1,"valume":1,"description":"fresh","price":{"amount":"89.90","currency":"RUB"},` +
+				`"category":"food","attributes":{"fat":3.2},"options":["pack"],"variants":[{"id":"3d2c1b0a-9f8e-4d7c-8b6a-5f4e3d2c1b0a","options":{"pack":"big"},"weight":1.1,"valume":1,` +
+				`"price":{"amount":"99.90","currency":"RUB"},"barcode":"4601234567890","visible":true,"in_stock":true,"available_quantity":7}],"in_stock":true,"available_quantity":7,"visible":false}` + "\n",
+		},
+		{
+			name:   "Unknown format",
+			format: "ods",
+			err:    ErrExportFormat,
+		},
+	}
+	logger := logrus.New()
+	mock, err := pgxmock.NewConn()
+	assert.Equal(t, err, nil)
+	defer mock.Close(context.Background())
+	s := NewService(repository.NewRepository(mock, logger), logger)
+	barcode := "4601234567890"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err == nil {
+				if tt.format != FormatJSONL {
+					mock.ExpectQuery("jsonb_object_keys").
+						WillReturnRows(mock.NewRows([]string{"name"}).AddRow("fat").AddRow("volume"))
+				}
+				mock.ExpectQuery("FROM products").
+					WithArgs("00000000-0000-0000-0000-000000000000", 500, true).
+					WillReturnRows(mock.NewRows([]string{"id", "name", "weight", "valume", "description", "photo", "price", "visible", "category", "attributes", "options", "available"}).
+						AddRow("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", "milk", 1.0, 1.0, "fresh", []string{}, int64(8990), false, "food", map[string]interface{}{"fat": 3.2}, []string{"pack"}, int64(7)))
+				mock.ExpectQuery("FROM variants").
+					WillReturnRows(mock.NewRows([]string{"id", "product_id", "options", "weight", "valume", "price", "barcode", "visible", "available"}).
+						AddRow("3d2c1b0a-9f8e-4d7c-8b6a-5f4e3d2c1b0a", "0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", map[string]string{"pack": "big"}, 1.1, 1.0, int64(9990), &barcode, true, int64(7)))
+				mock.ExpectQuery("FROM product_images").
+					WillReturnRows(mock.NewRows([]string{"product_id", "id", "key", "url", "content_type", "size", "position", "is_primary", "status", "sizes", "files"}))
+			}
+
+			var buf bytes.Buffer
+			err := s.ExportProducts(context.Background(), &buf, tt.format)
+			assert.Equal(t, err, tt.err)
+			assert.Equal(t, buf.String(), tt.file)
+			assert.Equal(t, mock.ExpectationsWereMet(), nil)
+		})
+	}
+}
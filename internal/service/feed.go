@@ -0,0 +1,132 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/EMus88/Market/internal/feed"
+	"github.com/EMus88/Market/internal/models"
+)
+
+var ErrFeed = errors.New("error: feeds are not configured")
+
+//products are discounted by promos in batches, so promos are read once per batch
+const feedBatch = 500
+
+//generated feed, it is served until ttl of generator passes
+type Feed struct {
+	Data      []byte
+	Generated time.Time
+}
+
+//last generated feed of every format, lock of format is held while feed is generated,
+//so concurrent requests wait for one generation
+type feedCache struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+	feeds map[string]*Feed
+}
+
+func newFeedCache() *feedCache {
+	return &feedCache{
+		locks: make(map[string]*sync.Mutex),
+		feeds: make(map[string]*Feed),
+	}
+}
+
+func (c *feedCache) lock(format string) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l, ok := c.locks[format]
+	if !ok {
+		l = &sync.Mutex{}
+		c.locks[format] = l
+	}
+	return l
+}
+
+func (c *feedCache) get(format string) *Feed {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.feeds[format]
+}
+
+func (c *feedCache) set(format string, f *Feed) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.feeds[format] = f
+}
+
+//feed of visible catalog from cache, it is generated again when it is older than ttl
+func (s *Service) Feed(ctx context.Context, format string) (*Feed, error) {
+	if s.Feeds == nil {
+		return nil, ErrFeed
+	}
+	if format != feed.FormatYML && format != feed.FormatGoogle {
+		return nil, feed.ErrFormat
+	}
+	l := s.feeds.lock(format)
+	l.Lock()
+	defer l.Unlock()
+	now := time.Now()
+	if f := s.feeds.get(format); f != nil && now.Sub(f.Generated) < s.Feeds.TTL {
+		return f, nil
+	}
+	f, err := s.generateFeed(ctx, format, now)
+	if err != nil {
+		//old feed is better than nothing for marketplace
+		if old := s.feeds.get(format); old != nil {
+			s.logger.Error(err)
+			return old, nil
+		}
+		return nil, err
+	}
+	s.feeds.set(format, f)
+	return f, nil
+}
+
+func (s *Service) generateFeed(ctx context.Context, format string, now time.Time) (*Feed, error) {
+	categories, err := s.Repository.GetCategories(false)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w, err := s.Feeds.NewWriter(&buf, format, categories, now)
+	if err != nil {
+		return nil, err
+	}
+	//offers have price with discount of automatic promos and price before it
+	batch := make([]models.ProductDTO, 0, feedBatch)
+	flush := func() error {
+		if err := s.DiscountPrices(batch); err != nil {
+			return err
+		}
+		for i := range batch {
+			if err := w.Write(&batch[i]); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+	err = s.Repository.ExportProducts(ctx, false, func(product *models.ProductDTO) error {
+		batch = append(batch, *product)
+		if len(batch) < feedBatch {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return &Feed{Data: buf.Bytes(), Generated: now}, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EMus88/Market/internal/feed"
+	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/repository"
+
+	"github.com/go-playground/assert"
+	"github.com/pashagolub/pgxmock"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_Feed(t *testing.T) {
+	logger := logrus.New()
+	mock, err := pgxmock.NewConn()
+	assert.Equal(t, err, nil)
+	defer mock.Close(context.Background())
+	s := NewService(repository.NewRepository(mock, logger), logger)
+
+	_, err = s.Feed(context.Background(), feed.FormatYML)
+	assert.Equal(t, err, ErrFeed)
+
+	s.Feeds, err = feed.New(feed.Config{ProductURL: "https://shop.ru/product/{id}", Shop: feed.Shop{Name: "Market", URL: "https://shop.ru"}})
+	assert.Equal(t, err, nil)
+	_, err = s.Feed(context.Background(), "csv")
+	assert.Equal(t, err, feed.ErrFormat)
+
+	mock.ExpectQuery("FROM categories").
+		WithArgs(false).
+		WillReturnRows(mock.NewRows([]string{"id", "category", "visible", "parent_id"}).
+			AddRow("5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10", "food", true, nil))
+	mock.ExpectQuery("FROM products").
+		WithArgs("00000000-0000-0000-0000-000000000000", 500, false).
+		WillReturnRows(mock.NewRows([]string{"id", "name", "weight", "valume", "description", "photo", "price", "visible", "category", "attributes", "options", "available"}).
+			AddRow("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", "milk", 1.0, 1.0, "", []string{}, int64(8990), true, "food", map[string]interface{}{}, []string{}, int64(7)))
+	mock.ExpectQuery("FROM variants").
+		WillReturnRows(mock.NewRows([]string{"id", "product_id", "options", "weight", "valume", "price", "barcode", "visible", "available"}))
+	mock.ExpectQuery("FROM product_images").
+		WillReturnRows(mock.NewRows([]string{"product_id", "id", "key", "url", "content_type", "size", "position", "is_primary", "status", "sizes", "files"}))
+	mock.ExpectQuery("FROM promos").
+		WillReturnRows(mock.NewRows([]string{"id", "code", "name", "type", "value", "scope", "targets", "min_total", "starts_at", "ends_at", "usage_limit", "per_user_limit", "used", "stackable", "active"}).
+			AddRow("3c4d5e6f-7a8b-4c9d-8e1f-2a3b4c5d6e7f", "", "milk week", models.DiscountPercent, int64(10), models.ScopeCategory, []string{"food"},
+				int64(0), (*time.Time)(nil), (*time.Time)(nil), (*int64)(nil), (*int64)(nil), int64(0), false, true))
+
+	first, err := s.Feed(context.Background(), feed.FormatYML)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.Contains(string(first.Data), `<offer id="0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21" available="true">`), true)
+	assert.Equal(t, strings.Contains(string(first.Data), `<price>80.91</price><oldprice>89.90</oldprice>`), true)
+	assert.Equal(t, mock.ExpectationsWereMet(), nil)
+
+	//feed is taken from cache until ttl passes
+	second, err := s.Feed(context.Background(), feed.FormatYML)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, second, first)
+
+	//old feed is served when it can't be generated
+	first.Generated = first.Generated.Add(-2 * time.Hour)
+	mock.ExpectQuery("FROM categories").WillReturnError(repository.ErrInternal)
+	third, err := s.Feed(context.Background(), feed.FormatYML)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, third, first)
+	assert.Equal(t, mock.ExpectationsWereMet(), nil)
+}
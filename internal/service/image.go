@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+)
+
+//max size of image if it is not set in config
+const defaultMaxImageSize = 5 << 20
+
+var (
+	ErrImage     = errors.New("error: invalid image")
+	ErrImageSize = errors.New("error: image is too large")
+)
+
+//allowed types of images with extensions of their files
+var imageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+func (s *Service) MaxImageSize() int64 {
+	if s.ImageSize <= 0 {
+		return defaultMaxImageSize
+	}
+	return s.ImageSize
+}
+
+//type of image is taken from its content, declared type of upload must be the same
+func ImageType(data []byte, declared string, maxSize int64) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("%w: file is empty", ErrImage)
+	}
+	if int64(len(data)) > maxSize {
+		return "", fmt.Errorf("%w: max size is %d bytes", ErrImageSize, maxSize)
+	}
+	detected := http.DetectContentType(data)
+	if _, ok := imageTypes[detected]; !ok {
+		return "", fmt.Errorf("%w: type %s is not allowed", ErrImage, detected)
+	}
+	if declared != "" && declared != "application/octet-stream" {
+		declared = strings.TrimSpace(strings.Split(declared, ";")[0])
+		if declared == "image/jpg" {
+			declared = "image/jpeg"
+		}
+		if declared != detected {
+			return "", fmt.Errorf("%w: declared type %s differs from content %s", ErrImage, declared, detected)
+		}
+	}
+	return detected, nil
+}
+
+//file of upload with type declared by client
+type ImageUpload struct {
+	Name     string
+	Data     []byte
+	Declared string
+}
+
+//save all images or none of them, every file is checked before any of them is stored
+//and images are attached to product in one transaction, first image becomes primary if primary is set
+func (s *Service) UploadImages(product uuid.UUID, uploads []ImageUpload, primary bool) error {
+	types := make([]string, len(uploads))
+	for i, u := range uploads {
+		contentType, err := ImageType(u.Data, u.Declared, s.MaxImageSize())
+		if err != nil {
+			return fmt.Errorf("%w (file %s)", err, u.Name)
+		}
+		types[i] = contentType
+	}
+	images := make([]*models.ImageDTO, 0, len(uploads))
+	keys := make([]string, 0, len(uploads))
+	for i, u := range uploads {
+		name, err := uuid.NewV4()
+		if err != nil {
+			s.deleteFiles(keys...)
+			return err
+		}
+		key := fmt.Sprintf("products/%s/%s%s", product, name, imageTypes[types[i]])
+		if err := s.Storage.Put(key, u.Data, types[i]); err != nil {
+			s.logger.Error(err)
+			s.deleteFiles(keys...)
+			return err
+		}
+		keys = append(keys, key)
+		images = append(images, &models.ImageDTO{
+			Key:         key,
+			URL:         s.Storage.URL(key),
+			ContentType: types[i],
+			Size:        int64(len(u.Data)),
+			Primary:     primary && i == 0,
+		})
+	}
+	if err := s.Repository.AddImages(product, images); err != nil {
+		s.deleteFiles(keys...)
+		return err
+	}
+	//sizes are generated by pipeline
+	select {
+	case s.imageQueue <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (s *Service) DeleteImage(product, id uuid.UUID) error {
+	keys, err := s.Repository.DeleteImage(product, id)
+	if err != nil {
+		return err
+	}
+	s.deleteFiles(keys...)
+	return nil
+}
+
+//files of images are deleted after product
+func (s *Service) DeleteProduct(id uuid.UUID) error {
+	images, err := s.Repository.GetImages(id)
+	if err != nil {
+		return err
+	}
+	if err := s.Repository.DeleteProduct(id); err != nil {
+		return err
+	}
+	for _, img := range images {
+		s.deleteFiles(append(img.Files, img.Key)...)
+	}
+	return nil
+}
+
+//file which is not deleted only takes place in storage, so error is logged
+func (s *Service) deleteFiles(keys ...string) {
+	for _, key := range keys {
+		if err := s.Storage.Delete(key); err != nil {
+			s.logger.Error(err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/EMus88/Market/internal/imaging"
+	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/repository"
+
+	"github.com/gofrs/uuid"
+)
+
+const (
+	//how often pending images are checked if it is not set in config
+	defaultImageInterval = time.Minute
+	//images taken from db at once
+	imageBatch = 10
+)
+
+//generate sizes of uploaded images until context is done, upload wakes up pipeline at once
+func (s *Service) RunImagePipeline(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultImageInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for {
+			images, err := s.Repository.ClaimImages(imageBatch)
+			if err != nil {
+				s.logger.Error(err)
+				break
+			}
+			for i := range images {
+				s.processImage(&images[i])
+			}
+			if len(images) < imageBatch || ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.imageQueue:
+		}
+	}
+}
+
+//image which can't be decoded is failed, on storage errors it is taken again later
+func (s *Service) processImage(img *models.ImageDTO) {
+	id := uuid.FromStringOrNil(img.ID)
+	data, err := s.Storage.Get(img.Key)
+	if err != nil {
+		s.logger.Error(err)
+		return
+	}
+	renditions, err := s.Images.Process(data)
+	if err != nil {
+		s.logger.Error(err)
+		img.Status = models.ImageFailed
+		if err := s.Repository.SetImageSizes(id, img); err != nil {
+			s.logger.Error(err)
+		}
+		return
+	}
+	//files of sizes are placed next to source: products/<product>/<image>_<size>.<ext>
+	base := strings.TrimSuffix(img.Key, imageTypes[img.ContentType])
+	sizes := make(map[string]models.ImageSize)
+	var files []string
+	for _, r := range renditions {
+		key := base + "_" + r.Size + r.Ext
+		if err := s.Storage.Put(key, r.Data, r.ContentType); err != nil {
+			s.logger.Error(err)
+			s.deleteFiles(files...)
+			return
+		}
+		files = append(files, key)
+		size := sizes[r.Size]
+		if r.Format == imaging.FormatWebP {
+			size.WebP = s.Storage.URL(key)
+		} else {
+			size.URL = s.Storage.URL(key)
+		}
+		size.Width, size.Height = r.Width, r.Height
+		sizes[r.Size] = size
+	}
+	img.Status = models.ImageReady
+	img.Sizes = sizes
+	img.Files = files
+	if err := s.Repository.SetImageSizes(id, img); err != nil {
+		//image was deleted while it was processed
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Error(err)
+		}
+		s.deleteFiles(files...)
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/EMus88/Market/internal/imaging"
+	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/repository"
+	"github.com/EMus88/Market/internal/storage"
+
+	"github.com/go-playground/assert"
+	"github.com/pashagolub/pgxmock"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_ProcessImage(t *testing.T) {
+	logger := logrus.New()
+	mock, err := pgxmock.NewConn()
+	assert.Equal(t, err, nil)
+	defer mock.Close(context.Background())
+
+	s := NewService(repository.NewRepository(mock, logger), logger)
+	local, _ := storage.NewLocal(t.TempDir(), "/images")
+	s.Storage = local
+	s.Images, _ = imaging.New(imaging.Config{Sizes: []imaging.Size{{Name: "thumb", Width: 20}}, WebP: true})
+
+	var source bytes.Buffer
+	png.Encode(&source, image.NewNRGBA(image.Rect(0, 0, 40, 30)))
+	local.Put("products/p/a.png", source.Bytes(), "image/png")
+	local.Put("products/p/b.png", []byte("\x89PNG\r\n\x1a\nbroken"), "image/png")
+
+	id := "5e8a2d1c-7b3f-4a6e-9c8d-2f1e3a4b5c6d"
+	mock.ExpectExec("UPDATE product_images").
+		WithArgs(pgxmock.AnyArg(), models.ImageReady, []byte(`{"thumb":{"url":"/images/products/p/a_thumb.png","webp":"/images/products/p/a_thumb.webp","width":20,"height":15}}`),
+			[]string{"products/p/a_thumb.png", "products/p/a_thumb.webp"}).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("UPDATE product_images").
+		WithArgs(pgxmock.AnyArg(), models.ImageFailed, []byte("{}"), []string(nil)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	s.processImage(&models.ImageDTO{ID: id, Key: "products/p/a.png", ContentType: "image/png"})
+	s.processImage(&models.ImageDTO{ID: id, Key: "products/p/b.png", ContentType: "image/png"})
+	assert.Equal(t, mock.ExpectationsWereMet(), nil)
+
+	data, err := local.Get("products/p/a_thumb.webp")
+	assert.Equal(t, err, nil)
+	img, format, err := image.Decode(bytes.NewReader(data))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, format, imaging.FormatWebP)
+	assert.Equal(t, img.Bounds().Dx(), 20)
+}
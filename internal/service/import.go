@@ -0,0 +1,338 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/spreadsheet"
+
+	"github.com/gofrs/uuid"
+)
+
+//max count of products in one file
+const maxImportRows = 10000
+
+//prefix of columns with attributes, like "attr:color"
+const attributePrefix = "attr:"
+
+var ErrImport = errors.New("error: invalid import file")
+
+//fields of product which can be set by columns of file
+var importFields = map[string]bool{
+	"name":        true,
+	"category":    true,
+	"price":       true,
+	"weight":      true,
+	"valume":      true,
+	"description": true,
+	"photo":       true,
+	"visible":     true,
+	"options":     true,
+}
+
+//these columns must be in every file
+var requiredFields = []string{"name", "category", "price", "weight", "valume"}
+
+//columns of file: index of column of every field and attribute
+type importColumns struct {
+	fields     map[string]int
+	attributes map[string]int
+	//titles of columns for report
+	titles []string
+}
+
+//columns by titles of first row, title is a field name or it is mapped to a field
+func ParseColumns(header []string, mapping map[string]string) (*importColumns, error) {
+	c := &importColumns{
+		fields:     make(map[string]int),
+		attributes: make(map[string]int),
+		titles:     header,
+	}
+	for i, title := range header {
+		title = strings.TrimSpace(title)
+		field, ok := mapping[title]
+		if !ok {
+			field = strings.ToLower(title)
+		}
+		switch {
+		case field == "":
+			continue
+		case strings.HasPrefix(field, attributePrefix) && len(field) > len(attributePrefix):
+			name := field[len(attributePrefix):]
+			if _, ok := c.attributes[name]; ok {
+				return nil, fmt.Errorf("%w: column of attribute %s is repeated", ErrImport, name)
+			}
+			c.attributes[name] = i
+		case importFields[field]:
+			if _, ok := c.fields[field]; ok {
+				return nil, fmt.Errorf("%w: column %s is repeated", ErrImport, field)
+			}
+			c.fields[field] = i
+		default:
+			return nil, fmt.Errorf("%w: unknown column %q", ErrImport, title)
+		}
+	}
+	for _, field := range requiredFields {
+		if _, ok := c.fields[field]; !ok {
+			return nil, fmt.Errorf("%w: column %s is required", ErrImport, field)
+		}
+	}
+	return c, nil
+}
+
+//check rows of file and create or update products by name in one transaction, nothing is written on dry run
+func (s *Service) ImportProducts(rows []spreadsheet.Row, mapping map[string]string, dryRun bool, user *uuid.UUID) (*models.ImportReport, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrImport)
+	}
+	if len(rows)-1 > maxImportRows {
+		return nil, fmt.Errorf("%w: max %d products in file", ErrImport, maxImportRows)
+	}
+	columns, err := ParseColumns(rows[0].Cells, mapping)
+	if err != nil {
+		return nil, err
+	}
+	rows = rows[1:]
+	report := &models.ImportReport{DryRun: dryRun, Rows: len(rows)}
+
+	//existing products, categories and their attributes
+	names := make([]string, len(rows))
+	for i, row := range rows {
+		names[i] = strings.TrimSpace(columns.cell(row, "name"))
+	}
+	existing, err := s.Repository.GetProductsByName(names)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.Repository.GetCategories(true)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		known[category.Name] = true
+	}
+	schemas := make(map[string][]models.CategoryAttribute)
+
+	products := make([]models.ImportRow, 0, len(rows))
+	seen := make(map[string]int, len(rows))
+	for _, row := range rows {
+		product, errs := columns.product(row, existing)
+		if product.Name != "" {
+			if first, ok := seen[product.Name]; ok {
+				errs = append(errs, columns.error(row, "name", fmt.Sprintf("product is repeated, first in row %d", first)))
+			}
+			seen[product.Name] = row.Number
+		}
+		if product.Category != "" && !known[product.Category] {
+			errs = append(errs, columns.error(row, "category", "category not found"))
+		}
+		if len(errs) == 0 {
+			errs = s.checkImported(columns, row, &product, existing, schemas)
+		}
+		if len(errs) > 0 {
+			report.Errors = append(report.Errors, errs...)
+			continue
+		}
+		if _, ok := existing[product.Name]; ok {
+			report.Updated++
+		} else {
+			report.Created++
+		}
+		products = append(products, models.ImportRow{Row: row.Number, Product: product})
+	}
+	if len(report.Errors) > 0 || dryRun {
+		return report, nil
+	}
+	written, err := s.Repository.ImportProducts(products, user)
+	if err != nil {
+		return nil, err
+	}
+	written.Rows = report.Rows
+	return written, nil
+}
+
+//attributes by schema of category, options of product with variants can't be changed
+func (s *Service) checkImported(c *importColumns, row spreadsheet.Row, product *models.ProductDTO, existing map[string]models.ProductDTO,
+	schemas map[string][]models.CategoryAttribute) []models.ImportError {
+	var errs []models.ImportError
+	schema, ok := schemas[product.Category]
+	if !ok {
+		var err error
+		if schema, err = s.Repository.GetAttributesByName(product.Category); err != nil {
+			return []models.ImportError{c.error(row, "category", err.Error())}
+		}
+		schemas[product.Category] = schema
+	}
+	if len(c.attributes) > 0 {
+		types := make(map[string]string, len(schema))
+		for _, a := range schema {
+			types[a.Name] = a.Type
+		}
+		//attributes without columns are kept, empty cell removes attribute
+		attributes := make(map[string]interface{}, len(product.Attributes)+len(c.attributes))
+		for name, value := range product.Attributes {
+			attributes[name] = value
+		}
+		product.Attributes = attributes
+		for name, i := range c.attributes {
+			value := strings.TrimSpace(cellAt(row, i))
+			if value == "" {
+				delete(product.Attributes, name)
+				continue
+			}
+			typed, err := attributeValue(types[name], value)
+			if err != nil {
+				errs = append(errs, models.ImportError{Row: row.Number, Column: c.titles[i], Error: err.Error()})
+				continue
+			}
+			product.Attributes[name] = typed
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+	}
+	if err := ValidateAttributes(schema, product.Attributes); err != nil {
+		return []models.ImportError{{Row: row.Number, Error: err.Error()}}
+	}
+	if old, ok := existing[product.Name]; ok && len(old.Variants) > 0 && !sameOptions(old.Options, product.Options) {
+		return []models.ImportError{c.error(row, "options", fmt.Errorf("%w: product has variants", ErrOptions).Error())}
+	}
+	return nil
+}
+
+//product of row, fields without columns are taken from existing product
+func (c *importColumns) product(row spreadsheet.Row, existing map[string]models.ProductDTO) (models.ProductDTO, []models.ImportError) {
+	var errs []models.ImportError
+	fail := func(field, reason string) {
+		errs = append(errs, c.error(row, field, reason))
+	}
+	product := models.ProductDTO{Visible: true}
+	product.Name = strings.TrimSpace(c.cell(row, "name"))
+	if old, ok := existing[product.Name]; ok {
+		product = old
+		product.Variants = nil
+	}
+	switch {
+	case product.Name == "":
+		fail("name", "name is required")
+	case utf8.RuneCountInString(product.Name) > 150:
+		fail("name", "max length is 150")
+	}
+	if product.Category = strings.TrimSpace(c.cell(row, "category")); product.Category == "" {
+		fail("category", "category is required")
+	}
+	price, err := models.ParseMoney(decimal(c.cell(row, "price")), models.BaseCurrency)
+	if err != nil || price.Amount <= 0 {
+		fail("price", "price must be positive amount like 89.90")
+	}
+	product.Price = price
+	for _, field := range []string{"weight", "valume"} {
+		value, err := strconv.ParseFloat(decimal(c.cell(row, field)), 64)
+		//NaN is not positive and is rejected too
+		if err != nil || !(value > 0) || math.IsInf(value, 0) {
+			fail(field, field+" must be positive number")
+			continue
+		}
+		//round to 2 decimal places as on adding of product
+		value = math.Round(value*100) / 100
+		if field == "weight" {
+			product.Weight = value
+		} else {
+			product.Valume = value
+		}
+	}
+	if c.has("description") {
+		product.Description = strings.TrimSpace(c.cell(row, "description"))
+		if utf8.RuneCountInString(product.Description) > 255 {
+			fail("description", "max length is 255")
+		}
+	}
+	if c.has("photo") {
+		product.Photo = list(c.cell(row, "photo"), "|")
+	}
+	if c.has("visible") {
+		if value := strings.TrimSpace(c.cell(row, "visible")); value != "" {
+			visible, err := strconv.ParseBool(value)
+			if err != nil {
+				fail("visible", "visible must be true or false")
+			}
+			product.Visible = visible
+		}
+	}
+	if c.has("options") {
+		product.Options = list(c.cell(row, "options"), ",")
+	}
+	return product, errs
+}
+
+func (c *importColumns) has(field string) bool {
+	_, ok := c.fields[field]
+	return ok
+}
+
+func (c *importColumns) cell(row spreadsheet.Row, field string) string {
+	i, ok := c.fields[field]
+	if !ok {
+		return ""
+	}
+	return cellAt(row, i)
+}
+
+func (c *importColumns) error(row spreadsheet.Row, field, reason string) models.ImportError {
+	e := models.ImportError{Row: row.Number, Error: reason}
+	if i, ok := c.fields[field]; ok {
+		e.Column = c.titles[i]
+	}
+	return e
+}
+
+//rows can be shorter than header
+func cellAt(row spreadsheet.Row, i int) string {
+	if i >= len(row.Cells) {
+		return ""
+	}
+	return row.Cells[i]
+}
+
+//value of attribute by its type in schema, unknown attributes are checked by schema later
+func attributeValue(t, value string) (interface{}, error) {
+	switch t {
+	case "number":
+		number, err := strconv.ParseFloat(decimal(value), 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, fmt.Errorf("%w: %s is not a number", ErrAttributes, value)
+		}
+		return number, nil
+	case "bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s is not true or false", ErrAttributes, value)
+		}
+		return b, nil
+	default:
+		return value, nil
+	}
+}
+
+//decimal comma and spaces between thousands are written by spreadsheets with russian locale
+func decimal(s string) string {
+	s = strings.NewReplacer(" ", "", "\u00a0", "").Replace(s)
+	return strings.Replace(s, ",", ".", 1)
+}
+
+//not empty trimmed parts of cell
+func list(cell, sep string) []string {
+	var parts []string
+	for _, part := range strings.Split(cell, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/repository"
+	"github.com/EMus88/Market/internal/spreadsheet"
+
+	"github.com/go-playground/assert"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_ParseColumns(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		ok     bool
+	}{
+		{name: "Ok", header: "Наименование,category,Цена,weight,valume,attr:fat", ok: true},
+		{name: "Required column", header: "Наименование,category,weight,valume"},
+		{name: "Unknown column", header: "Наименование,category,Цена,weight,valume,color"},
+		{name: "Repeated column", header: "Наименование,name,category,Цена,weight,valume"},
+	}
+	mapping := map[string]string{"Наименование": "name", "Цена": "price"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseColumns(strings.Split(tt.header, ","), mapping)
+			assert.Equal(t, err == nil, tt.ok)
+		})
+	}
+}
+
+func Test_ImportProducts(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		dryRun bool
+		schema bool
+		report models.ImportReport
+	}{
+		{
+			name: "Errors",
+			file: "name;category;price;weight;valume;attr:fat\nmilk;food;0;1;1;3.2\nbread;toys;49,90;0,5;1;\nmilk;food;99,90;1;1;много\nsalt;food;49,90;NaN;1;1\n",
+			report: models.ImportReport{Rows: 4, Errors: []models.ImportError{
+				{Row: 2, Column: "price", Error: "price must be positive amount like 89.90"},
+				{Row: 3, Column: "category", Error: "category not found"},
+				{Row: 4, Column: "name", Error: "product is repeated, first in row 2"},
+				{Row: 5, Column: "weight", Error: "weight must be positive number"},
+			}},
+		},
+		{
+			name:   "Not number attribute",
+			file:   "name;category;price;weight;valume;attr:fat\nkefir;food;99,90;1;1;Inf\n",
+			schema: true,
+			report: models.ImportReport{Rows: 1, Errors: []models.ImportError{
+				{Row: 2, Column: "attr:fat", Error: "error: invalid attributes: Inf is not a number"},
+			}},
+		},
+		{
+			name:   "Dry run",
+			file:   "name;category;price;weight;valume;attr:fat\nmilk;food;99,90;1;1;3,2\nkefir;food;1 079,90;1;0,5;2.5\n",
+			dryRun: true,
+			schema: true,
+			report: models.ImportReport{DryRun: true, Rows: 2, Created: 1, Updated: 1},
+		},
+		{
+			name:   "Ok",
+			file:   "name;category;price;weight;valume;attr:fat\nmilk;food;99,90;1;1;3,2\nkefir;food;1 079,90;1;0,5;2.5\n",
+			schema: true,
+			report: models.ImportReport{Rows: 2, Created: 1, Updated: 1},
+		},
+	}
+	logger := logrus.New()
+	mock, err := pgxmock.NewConn()
+	assert.Equal(t, err, nil)
+	defer mock.Close(context.Background())
+	s := NewService(repository.NewRepository(mock, logger), logger)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery("FROM products").
+				WillReturnRows(mock.NewRows([]string{"id", "name", "weight", "valume", "description", "photo", "price", "visible", "category", "attributes", "options"}).
+					AddRow("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", "milk", 1.0, 1.0, "", []string{}, int64(8990), true, "food", map[string]interface{}{"fat": 2.5, "brand": "farm"}, []string{}))
+			mock.ExpectQuery("FROM variants").
+				WillReturnRows(mock.NewRows([]string{"id", "product_id", "options", "weight", "valume", "price", "barcode", "visible", "available"}))
+			mock.ExpectQuery("FROM categories").
+				WillReturnRows(mock.NewRows([]string{"id", "category", "visible", "parent_id"}).
+					AddRow("5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10", "food", true, nil))
+			if tt.schema {
+				mock.ExpectQuery("FROM category_attributes").
+					WithArgs("food").
+					WillReturnRows(mock.NewRows([]string{"id", "category_id", "name", "type", "unit", "required"}).
+						AddRow("9a1e4c2b-3f5d-4e6a-8b7c-1d2e3f4a5b6c", "5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10", "fat", "number", "%", true).
+						AddRow("3f5d4e6a-9a1e-4c2b-8b7c-1d2e3f4a5b6c", "5b0f4d2e-8d1c-4a55-9d0a-0b7a2c3c9f10", "brand", "string", "", false))
+			}
+			if tt.name == "Ok" {
+				mock.ExpectBegin()
+				mock.ExpectQuery("FOR UPDATE").WithArgs("milk").
+					WillReturnRows(mock.NewRows([]string{"id", "price"}).AddRow("0c6f1b8e-62a4-4c8f-8c55-3f1e2a9b7d21", int64(8990)))
+				mock.ExpectExec("UPDATE products").
+					WithArgs(pgxmock.AnyArg(), 1.0, 1.0, "", []string{}, int64(9990), true, map[string]interface{}{"fat": 3.2, "brand": "farm"}, []string{}, "food").
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectExec("INSERT INTO price_histories").
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectQuery("FOR UPDATE").WithArgs("kefir").
+					WillReturnError(pgx.ErrNoRows)
+				mock.ExpectExec("INSERT INTO products").
+					WithArgs("kefir", 1.0, 0.5, "", []string(nil), int64(107990), true, map[string]interface{}{"fat": 2.5}, []string(nil), "food").
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+				mock.ExpectRollback()
+			}
+
+			rows, err := spreadsheet.Read(strings.NewReader(tt.file), spreadsheet.FormatCSV)
+			assert.Equal(t, err, nil)
+			report, err := s.ImportProducts(rows, nil, tt.dryRun, nil)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, *report, tt.report)
+			assert.Equal(t, mock.ExpectationsWereMet(), nil)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+)
+
+var (
+	ErrEmptyCart   = errors.New("error: cart is empty")
+	ErrCartInvalid = errors.New("error: cart has products which can't be bought")
+	ErrTransition  = errors.New("error: order status can't be changed this way")
+)
+
+//make order from cart, cart is returned when it has lines with warnings
+func (s *Service) Checkout(user uuid.UUID) (*models.OrderDTO, *models.Cart, error) {
+	cart, err := s.GetCart(user)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(cart.Lines) == 0 {
+		return nil, cart, ErrEmptyCart
+	}
+	if !cart.Valid {
+		return nil, cart, ErrCartInvalid
+	}
+	order, err := s.Repository.CreateOrder(user, cart)
+	if err != nil {
+		return nil, cart, err
+	}
+	return order, cart, nil
+}
+
+//move order to next status, owner limits change to own orders,
+//user is author of change and nil for system changes
+func (s *Service) ChangeOrderStatus(id uuid.UUID, to string, owner, user *uuid.UUID) (*models.OrderDTO, error) {
+	order, err := s.Repository.GetOrder(id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if !models.CanTransition(order.Status, to) {
+		return nil, ErrTransition
+	}
+	if err := s.Repository.UpdateOrderStatus(id, order.Status, to, user); err != nil {
+		return nil, err
+	}
+	return s.Repository.GetOrder(id, owner)
+}
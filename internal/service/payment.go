@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/payment"
+	"github.com/EMus88/Market/internal/repository"
+
+	"github.com/gofrs/uuid"
+)
+
+var (
+	ErrNotPayable     = errors.New("error: order can't be paid")
+	ErrNotRefundable  = errors.New("error: order has no succeeded payment")
+	ErrPaymentAmount  = errors.New("error: paid amount differs from payment")
+	ErrPaymentUnknown = errors.New("error: webhook of unknown payment")
+)
+
+//set payment provider, fake provider sends its webhooks right to service
+func (s *Service) UsePayment(g payment.Gateway) {
+	s.Payment = g
+	if fake, ok := g.(*payment.Fake); ok {
+		fake.Notify = func(body []byte, header http.Header) {
+			if err := s.HandleWebhook(body, header); err != nil {
+				s.logger.Error(err)
+			}
+		}
+	}
+}
+
+//start payment of created order, not finished payment is returned again
+func (s *Service) PayOrder(id, user uuid.UUID) (*models.PaymentDTO, error) {
+	order, err := s.Repository.GetOrder(id, &user)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderCreated {
+		return nil, ErrNotPayable
+	}
+	last, err := s.Repository.GetOrderPayment(id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if last != nil && last.Provider == s.Payment.Name() &&
+		(last.Status == models.PaymentPending || last.Status == models.PaymentAuthorized) {
+		return last, nil
+	}
+	intent, err := s.Payment.CreateIntent(order.ID, order.Total)
+	if err != nil {
+		s.logger.Error(err)
+		return nil, err
+	}
+	p := models.PaymentDTO{
+		OrderID:    order.ID,
+		Provider:   s.Payment.Name(),
+		IntentID:   intent.ID,
+		Amount:     order.Total,
+		Status:     models.PaymentPending,
+		ConfirmURL: intent.ConfirmURL,
+	}
+	if err := s.Repository.AddPayment(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+//return money of paid order, order is refunded by webhook of provider
+func (s *Service) RefundOrder(id uuid.UUID) (*models.PaymentDTO, error) {
+	order, err := s.Repository.GetOrder(id, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !models.CanTransition(order.Status, models.OrderRefunded) {
+		return nil, ErrTransition
+	}
+	p, err := s.Repository.GetOrderPayment(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotRefundable
+		}
+		return nil, err
+	}
+	if p.Status != models.PaymentSucceeded || p.Provider != s.Payment.Name() {
+		return nil, ErrNotRefundable
+	}
+	if err := s.Payment.Refund(p.IntentID, p.Amount); err != nil {
+		s.logger.Error(err)
+		return nil, err
+	}
+	return s.Repository.GetOrderPayment(id)
+}
+
+//process signed event of provider, event is claimed with its changes in one transaction,
+//so every event changes state only once
+func (s *Service) HandleWebhook(body []byte, header http.Header) error {
+	e, err := s.Payment.VerifyWebhook(body, header)
+	if err != nil {
+		return err
+	}
+	provider := s.Payment.Name()
+	p, err := s.Repository.GetPayment(provider, e.IntentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrPaymentUnknown
+		}
+		return err
+	}
+	if e.Amount.Amount != p.Amount.Amount {
+		return ErrPaymentAmount
+	}
+	order, err := s.Repository.GetOrder(uuid.FromStringOrNil(p.OrderID), nil)
+	if err != nil {
+		return err
+	}
+	change := models.PaymentChange{
+		Event:     models.PaymentEvent{ID: e.ID, Provider: provider, Type: e.Type},
+		PaymentID: uuid.FromStringOrNil(p.ID),
+		OrderID:   uuid.FromStringOrNil(p.OrderID),
+	}
+	switch e.Type {
+	case payment.EventAuthorized:
+		//capture and cancel are repeated safely by provider, so they are done before event is claimed,
+		//payment of cancelled order is released instead of charge
+		if order.Status == models.OrderCancelled {
+			err = s.Payment.Cancel(e.IntentID)
+		} else {
+			err = s.Payment.Capture(e.IntentID)
+		}
+		if err != nil {
+			return err
+		}
+		change.Status = models.PaymentAuthorized
+	case payment.EventSucceeded:
+		change.Status = models.PaymentSucceeded
+		//order was cancelled while payment was charged, money is returned
+		if order.Status == models.OrderCancelled {
+			if err := s.Payment.Refund(e.IntentID, p.Amount); err != nil {
+				return err
+			}
+			break
+		}
+		change.OrderStatus = models.OrderPaid
+	case payment.EventFailed:
+		change.Status = models.PaymentFailed
+	case payment.EventCancelled:
+		change.Status = models.PaymentCancelled
+	case payment.EventRefunded:
+		change.Status = models.PaymentRefunded
+		if order.Status != models.OrderCancelled {
+			change.OrderStatus = models.OrderRefunded
+		}
+	default:
+		return nil
+	}
+	_, err = s.Repository.ApplyPaymentEvent(&change)
+	return err
+}
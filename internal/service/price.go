@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EMus88/Market/internal/models"
+)
+
+//how often scheduled prices are checked if it is not set in config
+const defaultScheduleInterval = time.Minute
+
+var ErrSchedule = errors.New("error: invalid scheduled price")
+
+//scheduled price is positive, in base currency and starts in future
+func ValidateSchedule(s *models.ScheduledPriceDTO, now time.Time) error {
+	if s.Price.Amount <= 0 || s.Price.Currency != models.BaseCurrency {
+		return fmt.Errorf("%w: price must be positive and in %s", ErrSchedule, models.BaseCurrency)
+	}
+	if !s.StartsAt.After(now) {
+		return fmt.Errorf("%w: start time must be in future", ErrSchedule)
+	}
+	return nil
+}
+
+//apply scheduled prices when their time comes until context is done
+func (s *Service) RunPriceScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultScheduleInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		count, err := s.Repository.ApplyScheduledPrices()
+		if err != nil {
+			s.logger.Error(err)
+		} else if count > 0 {
+			s.logger.Infof("Scheduled prices applied: %d", count)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
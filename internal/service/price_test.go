@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/go-playground/assert"
+)
+
+func Test_ValidateSchedule(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		price models.ScheduledPriceDTO
+		ok    bool
+	}{
+		{name: "Ok", price: models.ScheduledPriceDTO{Price: models.NewMoney(9990, models.BaseCurrency), StartsAt: now.Add(time.Hour)}, ok: true},
+		{name: "In past", price: models.ScheduledPriceDTO{Price: models.NewMoney(9990, models.BaseCurrency), StartsAt: now.Add(-time.Hour)}},
+		{name: "Not base currency", price: models.ScheduledPriceDTO{Price: models.NewMoney(9990, "USD"), StartsAt: now.Add(time.Hour)}},
+		{name: "Zero price", price: models.ScheduledPriceDTO{Price: models.NewMoney(0, models.BaseCurrency), StartsAt: now.Add(time.Hour)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchedule(&tt.price, now)
+			assert.Equal(t, err == nil, tt.ok)
+		})
+	}
+}
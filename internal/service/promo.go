@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+)
+
+var ErrPromo = errors.New("error: invalid promo")
+
+//check rules of promo, code is kept in upper case
+func ValidatePromo(p *models.PromoDTO) error {
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	if p.Active == nil {
+		active := true
+		p.Active = &active
+	}
+	switch p.Type {
+	case models.DiscountPercent:
+		if p.Percent < 1 || p.Percent > 100 {
+			return fmt.Errorf("%w: percent must be from 1 to 100", ErrPromo)
+		}
+		p.Amount = nil
+	case models.DiscountFixed:
+		if p.Amount == nil || p.Amount.Amount <= 0 || p.Amount.Currency != models.BaseCurrency {
+			return fmt.Errorf("%w: amount must be positive and in %s", ErrPromo, models.BaseCurrency)
+		}
+		p.Percent = 0
+	}
+	if p.MinTotal != nil && (p.MinTotal.Amount < 0 || p.MinTotal.Currency != models.BaseCurrency) {
+		return fmt.Errorf("%w: min total must be in %s", ErrPromo, models.BaseCurrency)
+	}
+	switch p.Scope {
+	case models.ScopeCart:
+		if len(p.Targets) > 0 {
+			return fmt.Errorf("%w: cart promo has no targets", ErrPromo)
+		}
+	case models.ScopeProduct:
+		if len(p.Targets) == 0 {
+			return fmt.Errorf("%w: products are required", ErrPromo)
+		}
+		for _, target := range p.Targets {
+			if _, err := uuid.FromString(target); err != nil {
+				return fmt.Errorf("%w: %s is not product id", ErrPromo, target)
+			}
+		}
+	case models.ScopeCategory:
+		if len(p.Targets) == 0 {
+			return fmt.Errorf("%w: categories are required", ErrPromo)
+		}
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return fmt.Errorf("%w: promo ends before start", ErrPromo)
+	}
+	return nil
+}
+
+//discounted prices of products and variants by automatic promos of products and categories
+func (s *Service) DiscountPrices(products []models.ProductDTO) error {
+	if len(products) == 0 {
+		return nil
+	}
+	all, err := s.Repository.GetActivePromos(nil)
+	if err != nil {
+		return err
+	}
+	var promos []models.PromoDTO
+	for _, p := range all {
+		if p.Scope != models.ScopeCart && p.MinTotal == nil {
+			promos = append(promos, p)
+		}
+	}
+	if len(promos) == 0 {
+		return nil
+	}
+	//price of every product and variant is discounted as one item in cart
+	price := func(product *models.ProductDTO, price models.Money) *models.Money {
+		cart := NewCart([]models.CartLine{{
+			ProductID:  product.ID,
+			Category:   product.Category,
+			Price:      price,
+			AddedPrice: price,
+			Quantity:   1,
+			Available:  1,
+			Visible:    true,
+		}})
+		ApplyPromos(cart, promos)
+		if cart.Discount.Amount == 0 {
+			return nil
+		}
+		discounted := models.NewMoney(cart.Total.Amount, price.Currency)
+		return &discounted
+	}
+	for i := range products {
+		products[i].Discount = price(&products[i], products[i].Price)
+		for j := range products[i].Variants {
+			products[i].Variants[j].Discount = price(&products[i], products[i].Variants[j].Price)
+		}
+	}
+	return nil
+}
@@ -1,8 +1,16 @@
 package service
 
 import (
+	"context"
+	"time"
+
+	"github.com/EMus88/Market/internal/feed"
+	"github.com/EMus88/Market/internal/imaging"
 	"github.com/EMus88/Market/internal/models"
+	"github.com/EMus88/Market/internal/payment"
 	"github.com/EMus88/Market/internal/repository"
+	"github.com/EMus88/Market/internal/shipping"
+	"github.com/EMus88/Market/internal/storage"
 
 	"github.com/gofrs/uuid"
 	"github.com/sirupsen/logrus"
@@ -16,14 +24,88 @@ type Repository interface {
 	AddCategory(m *models.Category) error
 	AddProduct(m *models.ProductDTO) error
 	ChangeVisible(v *models.Visible) error
-	GetCatalog() ([]models.ProductDTO, error)
-	GetByCategory(productName string, category string) ([]models.ProductDTO, error)
-	GetByAllCategories(productName string) ([]models.ProductDTO, error)
+	GetCatalog(f *models.CatalogFilter, all bool) (*models.CatalogPage, error)
+	Search(f *models.SearchFilter, all bool) (*models.SearchResult, error)
+	Suggest(prefix string) (*models.Suggestions, error)
+	SetProductPrice(id uuid.UUID, price models.Money) error
+	DeleteProductPrice(id uuid.UUID, currency string) error
+	GetRates() ([]models.ExchangeRate, error)
+	SetRate(rate *models.ExchangeRate) error
+	ConvertPrices(products []models.ProductDTO, currency string) error
+	GetProduct(id uuid.UUID, all bool) (*models.ProductDTO, error)
+	UpdateProduct(id uuid.UUID, m *models.ProductUpdate, user *uuid.UUID) error
+	DeleteProduct(id uuid.UUID) error
+	GetCategories(all bool) ([]models.Category, error)
+	RenameCategory(id uuid.UUID, name string) error
+	DeleteCategory(id uuid.UUID) error
+	ChangeCategoryVisible(v *models.Visible) error
+	GetCategoryTree(root *uuid.UUID) ([]*models.CategoryNode, error)
+	MoveCategory(id uuid.UUID, parent *uuid.UUID) error
+	GetAttributes(id uuid.UUID) ([]models.CategoryAttribute, error)
+	GetAttributesByName(category string) ([]models.CategoryAttribute, error)
+	AddAttribute(a *models.CategoryAttribute) error
+	DeleteAttribute(category uuid.UUID, name string) error
+	AddVariant(productID uuid.UUID, v *models.VariantDTO) error
+	UpdateVariant(productID, id uuid.UUID, m *models.VariantUpdate) error
+	DeleteVariant(productID, id uuid.UUID) error
+	AddWarehouse(w *models.Warehouse) error
+	GetWarehouses() ([]models.Warehouse, error)
+	AdjustStock(a *models.StockAdjustment, user *uuid.UUID) (*models.StockMovement, error)
+	GetStock(productID uuid.UUID) ([]models.Stock, error)
+	GetMovements(f *models.MovementFilter) ([]models.StockMovement, error)
+	AddToCart(user uuid.UUID, item *models.CartItemDTO) error
+	UpdateCartItem(user, id uuid.UUID, quantity int64) error
+	DeleteCartItem(user, id uuid.UUID) error
+	GetCartLines(user uuid.UUID) ([]models.CartLine, error)
+	CreateOrder(user uuid.UUID, cart *models.Cart) (*models.OrderDTO, error)
+	UpdateOrderStatus(id uuid.UUID, from, to string, user *uuid.UUID) error
+	GetOrder(id uuid.UUID, user *uuid.UUID) (*models.OrderDTO, error)
+	GetOrders(f *models.OrderFilter, user *uuid.UUID) (*models.OrdersPage, error)
+	AddPayment(p *models.PaymentDTO) error
+	GetPayment(provider, intentID string) (*models.PaymentDTO, error)
+	GetOrderPayment(orderID uuid.UUID) (*models.PaymentDTO, error)
+	ApplyPaymentEvent(c *models.PaymentChange) (bool, error)
+	AddPromo(p *models.PromoDTO) error
+	UpdatePromo(id uuid.UUID, p *models.PromoDTO) error
+	DeletePromo(id uuid.UUID) error
+	GetPromo(id uuid.UUID) (*models.PromoDTO, error)
+	GetPromos() ([]models.PromoDTO, error)
+	GetActivePromos(user *uuid.UUID) ([]models.PromoDTO, error)
+	SetCartPromo(user uuid.UUID, code string) error
+	DeleteCartPromo(user uuid.UUID) error
+	GetPriceHistory(product uuid.UUID, f *models.PriceHistoryFilter) ([]models.PriceChange, error)
+	GetPriceAt(product uuid.UUID, at time.Time) (*models.PriceAt, error)
+	AddScheduledPrice(product uuid.UUID, s *models.ScheduledPriceDTO, user *uuid.UUID) error
+	GetScheduledPrices(product uuid.UUID) ([]models.ScheduledPriceDTO, error)
+	DeleteScheduledPrice(product, id uuid.UUID) error
+	ApplyScheduledPrices() (int, error)
+	AddImages(product uuid.UUID, images []*models.ImageDTO) error
+	GetImages(product uuid.UUID) ([]models.ImageDTO, error)
+	SetPrimaryImage(product, id uuid.UUID) error
+	SortImages(product uuid.UUID, ids []uuid.UUID) error
+	DeleteImage(product, id uuid.UUID) ([]string, error)
+	ClaimImages(limit int) ([]models.ImageDTO, error)
+	SetImageSizes(id uuid.UUID, img *models.ImageDTO) error
+	GetProductsByName(names []string) (map[string]models.ProductDTO, error)
+	ImportProducts(rows []models.ImportRow, user *uuid.UUID) (*models.ImportReport, error)
+	GetAttributeNames() ([]string, error)
+	ExportProducts(ctx context.Context, all bool, fn func(*models.ProductDTO) error) error
 }
 
 type Service struct {
 	Repository
 	Auth
+	Payment  payment.Gateway
+	Shipping *shipping.Calculator
+	Storage  storage.Storage
+	Images   *imaging.Pipeline
+	Feeds    *feed.Generator
+	//max size of uploaded image in bytes
+	ImageSize int64
+	//wakes up pipeline of images after upload
+	imageQueue chan struct{}
+	//generated feeds of catalog
+	feeds  *feedCache
 	logger *logrus.Logger
 }
 
@@ -31,6 +113,8 @@ func NewService(r *repository.Repository, logger *logrus.Logger) *Service {
 	return &Service{
 		Repository: r,
 		Auth:       *NewAuth(r),
+		imageQueue: make(chan struct{}, 1),
+		feeds:      newFeedCache(),
 		logger:     logger,
 	}
 }
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/gofrs/uuid"
+)
+
+//prices of delivery of cart to region
+func (s *Service) QuoteCart(user uuid.UUID, region string) (*models.ShippingQuotes, error) {
+	lines, err := s.Repository.GetCartLines(user)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, ErrEmptyCart
+	}
+	var parcel models.Parcel
+	for _, line := range lines {
+		parcel.Add(line.Weight, line.Valume, line.Quantity)
+	}
+	return s.Shipping.Quote(region, parcel)
+}
+
+//prices of delivery of order to region by sizes of goods at purchase time,
+//owner limits search to own orders
+func (s *Service) QuoteOrder(id uuid.UUID, owner *uuid.UUID, region string) (*models.ShippingQuotes, error) {
+	order, err := s.Repository.GetOrder(id, owner)
+	if err != nil {
+		return nil, err
+	}
+	var parcel models.Parcel
+	for _, item := range order.Items {
+		parcel.Add(item.Weight, item.Valume, item.Quantity)
+	}
+	return s.Shipping.Quote(region, parcel)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+)
+
+var ErrOptions = errors.New("error: invalid variant options")
+
+//variant has value of each option of product and nothing else
+func ValidateOptions(names []string, options map[string]string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("%w: product has no options", ErrOptions)
+	}
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+		if options[name] == "" {
+			return fmt.Errorf("%w: %s is required", ErrOptions, name)
+		}
+	}
+	for name := range options {
+		if !known[name] {
+			return fmt.Errorf("%w: %s is unknown", ErrOptions, name)
+		}
+	}
+	return nil
+}
+
+//check options of variant by options of its product
+func (s *Service) CheckVariant(productID uuid.UUID, options map[string]string) error {
+	product, err := s.Repository.GetProduct(productID, true)
+	if err != nil {
+		return err
+	}
+	return ValidateOptions(product.Options, options)
+}
+
+//same set of option names in any order
+func sameOptions(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, name := range a {
+		set[name] = true
+	}
+	for _, name := range b {
+		if !set[name] {
+			return false
+		}
+	}
+	return true
+}
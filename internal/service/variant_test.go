@@ -0,0 +1,30 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/assert"
+)
+
+func Test_ValidateOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		options map[string]string
+		valid   bool
+	}{
+		{name: "Ok", names: []string{"size", "color"}, options: map[string]string{"size": "XL", "color": "red"}, valid: true},
+		{name: "No options", names: nil, options: map[string]string{"size": "XL"}},
+		{name: "Missing", names: []string{"size", "color"}, options: map[string]string{"size": "XL"}},
+		{name: "Empty value", names: []string{"size"}, options: map[string]string{"size": ""}},
+		{name: "Unknown", names: []string{"size"}, options: map[string]string{"size": "XL", "color": "red"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOptions(tt.names, tt.options)
+			assert.Equal(t, err == nil, tt.valid)
+			assert.Equal(t, errors.Is(err, ErrOptions), !tt.valid)
+		})
+	}
+}
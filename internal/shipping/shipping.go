@@ -0,0 +1,220 @@
+package shipping
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/spf13/viper"
+)
+
+//types of tariffs
+const (
+	//fixed price for any parcel
+	TariffFlat = "flat"
+	//price of first tier which takes weight of parcel
+	TariffWeight = "weight"
+	//base price and price of every started kg of chargeable weight
+	TariffVolumetric = "volumetric"
+)
+
+//zone for regions which are not listed in other zones
+const anyRegion = "*"
+
+//cm3 of parcel for one kg of volumetric weight
+const defaultDivisor = 5000
+
+var (
+	ErrNoZone = errors.New("error: no shipping zone for region")
+	ErrConfig = errors.New("error: not valid shipping tariffs")
+)
+
+//tables of zones and tariffs from config
+type Config struct {
+	Divisor float64 `mapstructure:"volumetric_divisor"`
+	Zones   []Zone  `mapstructure:"zones"`
+}
+
+type Zone struct {
+	Name    string   `mapstructure:"name"`
+	Regions []string `mapstructure:"regions"`
+	Tariffs []Tariff `mapstructure:"tariffs"`
+}
+
+//prices are set in base currency like "300.00"
+type Tariff struct {
+	Name  string `mapstructure:"name"`
+	Type  string `mapstructure:"type"`
+	Price string `mapstructure:"price"`
+	PerKg string `mapstructure:"per_kg"`
+	//volumetric weight is used by weight tariff too when it is set
+	Divisor float64 `mapstructure:"divisor"`
+	Tiers   []Tier  `mapstructure:"tiers"`
+}
+
+//price for parcel up to weight in kg
+type Tier struct {
+	UpTo  float64 `mapstructure:"up_to"`
+	Price string  `mapstructure:"price"`
+}
+
+type tariff struct {
+	name    string
+	kind    string
+	price   int64
+	perKg   int64
+	divisor float64
+	tiers   []tier
+}
+
+type tier struct {
+	upTo  float64
+	price int64
+}
+
+type zone struct {
+	name    string
+	tariffs []tariff
+}
+
+//prices delivery of parcel by tariffs of its zone
+type Calculator struct {
+	divisor float64
+	regions map[string]*zone
+}
+
+//calculator from shipping section of config
+func Load() (*Calculator, error) {
+	var cfg Config
+	if err := viper.UnmarshalKey("shipping", &cfg); err != nil {
+		return nil, err
+	}
+	return New(cfg)
+}
+
+func New(cfg Config) (*Calculator, error) {
+	c := Calculator{divisor: cfg.Divisor, regions: make(map[string]*zone)}
+	if c.divisor <= 0 {
+		c.divisor = defaultDivisor
+	}
+	for _, z := range cfg.Zones {
+		parsed := &zone{name: z.Name}
+		for _, t := range z.Tariffs {
+			pt, err := parseTariff(t)
+			if err != nil {
+				return nil, err
+			}
+			parsed.tariffs = append(parsed.tariffs, pt)
+		}
+		for _, region := range z.Regions {
+			region = strings.ToLower(strings.TrimSpace(region))
+			if _, ok := c.regions[region]; ok {
+				return nil, ErrConfig
+			}
+			c.regions[region] = parsed
+		}
+	}
+	return &c, nil
+}
+
+func parseTariff(t Tariff) (tariff, error) {
+	pt := tariff{name: t.Name, kind: t.Type, divisor: t.Divisor}
+	var err error
+	if t.Price != "" {
+		if pt.price, err = parsePrice(t.Price); err != nil {
+			return pt, err
+		}
+	}
+	switch t.Type {
+	case TariffFlat:
+	case TariffWeight:
+		if len(t.Tiers) == 0 {
+			return pt, ErrConfig
+		}
+		for i, tr := range t.Tiers {
+			price, err := parsePrice(tr.Price)
+			if err != nil {
+				return pt, err
+			}
+			//tiers go by growing weight
+			if tr.UpTo <= 0 || (i > 0 && tr.UpTo <= t.Tiers[i-1].UpTo) {
+				return pt, ErrConfig
+			}
+			pt.tiers = append(pt.tiers, tier{upTo: tr.UpTo, price: price})
+		}
+	case TariffVolumetric:
+		if pt.perKg, err = parsePrice(t.PerKg); err != nil {
+			return pt, err
+		}
+	default:
+		return pt, ErrConfig
+	}
+	return pt, nil
+}
+
+func parsePrice(s string) (int64, error) {
+	price, err := models.ParseMoney(s, models.BaseCurrency)
+	if err != nil || price.Amount < 0 {
+		return 0, ErrConfig
+	}
+	return price.Amount, nil
+}
+
+//valume in litres converted to weight in kg
+func volumetricWeight(valume, divisor float64) float64 {
+	return valume * 1000 / divisor
+}
+
+//prices of delivery to region by all tariffs which take parcel
+func (c *Calculator) Quote(region string, p models.Parcel) (*models.ShippingQuotes, error) {
+	z, ok := c.regions[strings.ToLower(strings.TrimSpace(region))]
+	if !ok {
+		if z, ok = c.regions[anyRegion]; !ok {
+			return nil, ErrNoZone
+		}
+	}
+	quotes := models.ShippingQuotes{
+		Region:           region,
+		Parcel:           p,
+		VolumetricWeight: volumetricWeight(p.Valume, c.divisor),
+		Quotes:           []models.ShippingQuote{},
+	}
+	for _, t := range z.tariffs {
+		if quote, ok := t.quote(p, c.divisor); ok {
+			quote.Zone = z.name
+			quotes.Quotes = append(quotes.Quotes, quote)
+		}
+	}
+	return &quotes, nil
+}
+
+//price of parcel, heavier parcel than last tier is not taken
+func (t tariff) quote(p models.Parcel, divisor float64) (models.ShippingQuote, bool) {
+	quote := models.ShippingQuote{Tariff: t.name, Type: t.kind, ChargeableWeight: p.Weight}
+	if t.divisor > 0 {
+		divisor = t.divisor
+	}
+	if t.kind == TariffVolumetric || t.divisor > 0 {
+		quote.ChargeableWeight = math.Max(p.Weight, volumetricWeight(p.Valume, divisor))
+	}
+	price := t.price
+	switch t.kind {
+	case TariffWeight:
+		found := false
+		for _, tr := range t.tiers {
+			if quote.ChargeableWeight <= tr.upTo {
+				price, found = tr.price, true
+				break
+			}
+		}
+		if !found {
+			return quote, false
+		}
+	case TariffVolumetric:
+		price += t.perKg * int64(math.Ceil(quote.ChargeableWeight))
+	}
+	quote.Price = models.NewMoney(price, models.BaseCurrency)
+	return quote, true
+}
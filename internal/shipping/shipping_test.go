@@ -0,0 +1,102 @@
+package shipping
+
+import (
+	"testing"
+
+	"github.com/EMus88/Market/internal/models"
+
+	"github.com/go-playground/assert"
+)
+
+func Test_Quote(t *testing.T) {
+	c, err := New(Config{
+		Zones: []Zone{
+			{
+				Name:    "local",
+				Regions: []string{"Moscow"},
+				Tariffs: []Tariff{{Name: "courier", Type: TariffFlat, Price: "300.00"}},
+			},
+			{
+				Name:    "russia",
+				Regions: []string{"*"},
+				Tariffs: []Tariff{
+					{Name: "post", Type: TariffWeight, Tiers: []Tier{{UpTo: 1, Price: "250.00"}, {UpTo: 5, Price: "450.00"}}},
+					{Name: "post volumetric", Type: TariffWeight, Divisor: 4000, Tiers: []Tier{{UpTo: 5, Price: "500.00"}}},
+					{Name: "express", Type: TariffVolumetric, Price: "400.00", PerKg: "60.00"},
+				},
+			},
+		},
+	})
+	assert.Equal(t, err, nil)
+	tests := []struct {
+		name   string
+		region string
+		parcel models.Parcel
+		want   map[string]int64
+	}{
+		{
+			name:   "Flat",
+			region: "moscow",
+			parcel: models.Parcel{Weight: 30, Valume: 100},
+			want:   map[string]int64{"courier": 30000},
+		},
+		{
+			name:   "Light parcel",
+			region: "tver",
+			parcel: models.Parcel{Weight: 0.5, Valume: 1},
+			want:   map[string]int64{"post": 25000, "post volumetric": 50000, "express": 46000},
+		},
+		{
+			//volumetric weight 20 litres / 5000 = 4 kg and 5 kg by own divisor
+			name:   "Big parcel",
+			region: "tver",
+			parcel: models.Parcel{Weight: 2, Valume: 20},
+			want:   map[string]int64{"post": 45000, "post volumetric": 50000, "express": 64000},
+		},
+		{
+			name:   "Too heavy for post",
+			region: "tver",
+			parcel: models.Parcel{Weight: 6.2, Valume: 1},
+			want:   map[string]int64{"express": 82000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotes, err := c.Quote(tt.region, tt.parcel)
+			assert.Equal(t, err, nil)
+			got := make(map[string]int64)
+			for _, q := range quotes.Quotes {
+				got[q.Tariff] = q.Price.Amount
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	tests := []struct {
+		name   string
+		tariff Tariff
+		err    error
+	}{
+		{name: "Ok", tariff: Tariff{Type: TariffFlat, Price: "10"}},
+		{name: "Unknown type", tariff: Tariff{Type: "free"}, err: ErrConfig},
+		{name: "Bad price", tariff: Tariff{Type: TariffFlat, Price: "ten"}, err: ErrConfig},
+		{name: "No tiers", tariff: Tariff{Type: TariffWeight}, err: ErrConfig},
+		{name: "Wrong order of tiers", tariff: Tariff{Type: TariffWeight, Tiers: []Tier{{UpTo: 5, Price: "1"}, {UpTo: 1, Price: "2"}}}, err: ErrConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(Config{Zones: []Zone{{Regions: []string{"*"}, Tariffs: []Tariff{tt.tariff}}}})
+			assert.Equal(t, err, tt.err)
+		})
+	}
+	_, err := New(Config{})
+	assert.Equal(t, err, nil)
+}
+
+func Test_NoZone(t *testing.T) {
+	c, _ := New(Config{Zones: []Zone{{Regions: []string{"moscow"}}}})
+	_, err := c.Quote("tver", models.Parcel{Weight: 1})
+	assert.Equal(t, err, ErrNoZone)
+}
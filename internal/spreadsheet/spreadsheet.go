@@ -0,0 +1,117 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+//formats of files
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var (
+	ErrFormat = errors.New("error: unknown file format")
+	ErrFile   = errors.New("error: file can't be read")
+)
+
+//format by extension of file name
+func FormatOf(name string) string {
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+}
+
+//row of file with its number in file
+type Row struct {
+	Number int
+	Cells  []string
+}
+
+//rows of csv file or of first sheet of xlsx file, empty rows are skipped
+func Read(r io.Reader, format string) ([]Row, error) {
+	var rows []Row
+	var err error
+	switch format {
+	case FormatCSV:
+		rows, err = readCSV(r)
+	case FormatXLSX:
+		rows, err = readXLSX(r)
+	default:
+		return nil, ErrFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFile, err)
+	}
+	return rows, nil
+}
+
+//separator is comma or semicolon (excel with russian locale), it is taken from first line
+func readCSV(r io.Reader) ([]Row, error) {
+	br := bufio.NewReader(r)
+	//utf-8 byte order mark which is written by excel
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		br.Discard(3)
+	}
+	first, _ := br.Peek(4096)
+	if i := bytes.IndexByte(first, '\n'); i >= 0 {
+		first = first[:i]
+	}
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	if bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		reader.Comma = ';'
+	}
+	var rows []Row
+	for {
+		cells, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !empty(cells) {
+			line, _ := reader.FieldPos(0)
+			rows = append(rows, Row{Number: line, Cells: cells})
+		}
+	}
+}
+
+func readXLSX(r io.Reader) ([]Row, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+	all, err := f.GetRows(sheets[0])
+	if err != nil {
+		return nil, err
+	}
+	var rows []Row
+	for i, cells := range all {
+		if !empty(cells) {
+			rows = append(rows, Row{Number: i + 1, Cells: cells})
+		}
+	}
+	return rows, nil
+}
+
+func empty(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
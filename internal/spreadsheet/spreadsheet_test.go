@@ -0,0 +1,53 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func Test_ReadCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		rows []Row
+	}{
+		{
+			name: "Comma",
+			file: "name,price\nmilk,\"89,90\"\n",
+			rows: []Row{{Number: 1, Cells: []string{"name", "price"}}, {Number: 2, Cells: []string{"milk", "89,90"}}},
+		},
+		{
+			name: "Semicolon with bom and empty rows",
+			file: "\xef\xbb\xbfname;price\n;\n\nmilk;89,90\r\n",
+			rows: []Row{{Number: 1, Cells: []string{"name", "price"}}, {Number: 4, Cells: []string{"milk", "89,90"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Read(strings.NewReader(tt.file), FormatCSV)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, rows, tt.rows)
+		})
+	}
+}
+
+func Test_ReadXLSX(t *testing.T) {
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]interface{}{"name", "price"})
+	f.SetSheetRow("Sheet1", "A3", &[]interface{}{"milk", 89.9})
+	var buf bytes.Buffer
+	assert.Equal(t, f.Write(&buf), nil)
+
+	rows, err := Read(&buf, FormatOf("prices.XLSX"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, rows, []Row{{Number: 1, Cells: []string{"name", "price"}}, {Number: 3, Cells: []string{"milk", "89.9"}}})
+
+	_, err = Read(strings.NewReader("not zip"), FormatXLSX)
+	assert.NotEqual(t, err, nil)
+	_, err = Read(strings.NewReader(""), "ods")
+	assert.Equal(t, err, ErrFormat)
+}
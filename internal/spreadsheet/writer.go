@@ -0,0 +1,135 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+//decimal number like money amount "89.90", it is written to csv as is and to xlsx as number
+type Number string
+
+//writer of table rows, rows are written one by one and are not kept in memory
+type Writer interface {
+	//cells are strings, numbers, bools and nils
+	Write(cells []interface{}) error
+	//write end of file, writer can't be used after it
+	Close() error
+}
+
+//content type of file for download
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, ErrFormat
+}
+
+//csv with semicolon and byte order mark is opened by excel with russian locale without import dialog
+type csvWriter struct {
+	buf *bufio.Writer
+	csv *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	buf := bufio.NewWriter(w)
+	if _, err := buf.WriteString("\xef\xbb\xbf"); err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(buf)
+	writer.Comma = ';'
+	return &csvWriter{buf: buf, csv: writer}, nil
+}
+
+func (w *csvWriter) Write(cells []interface{}) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = text(cell)
+	}
+	return w.csv.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
+
+//rows of xlsx are written to temporary file by excelize when they are too many,
+//file is written to w on close, because xlsx is zip archive
+type xlsxWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{w: w, file: file, stream: stream}, nil
+}
+
+func (w *xlsxWriter) Write(cells []interface{}) error {
+	w.row++
+	axis, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(cells))
+	for i, cell := range cells {
+		values[i] = cell
+		if number, ok := cell.(Number); ok {
+			if f, err := strconv.ParseFloat(string(number), 64); err == nil {
+				values[i] = f
+			} else {
+				values[i] = string(number)
+			}
+		}
+	}
+	return w.stream.SetRow(axis, values)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	return w.file.Write(w.w)
+}
+
+func text(cell interface{}) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case Number:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(cell)
+}
@@ -0,0 +1,46 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-playground/assert"
+)
+
+func Test_Writer(t *testing.T) {
+	tests := []struct {
+		format string
+		rows   []Row
+	}{
+		{
+			format: FormatCSV,
+			rows: []Row{
+				{Number: 1, Cells: []string{"name", "price", "weight", "visible", "barcode"}},
+				{Number: 2, Cells: []string{"milk; 3.2%", "89.90", "1.5", "true", ""}},
+			},
+		},
+		{
+			format: FormatXLSX,
+			rows: []Row{
+				{Number: 1, Cells: []string{"name", "price", "weight", "visible", "barcode"}},
+				{Number: 2, Cells: []string{"milk; 3.2%", "89.9", "1.5", "1"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, tt.format)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, w.Write([]interface{}{"name", "price", "weight", "visible", "barcode"}), nil)
+			assert.Equal(t, w.Write([]interface{}{"milk; 3.2%", Number("89.90"), 1.5, true, nil}), nil)
+			assert.Equal(t, w.Close(), nil)
+
+			rows, err := Read(&buf, tt.format)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, rows, tt.rows)
+		})
+	}
+	_, err := NewWriter(&bytes.Buffer{}, "ods")
+	assert.Equal(t, err, ErrFormat)
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+//files in directory of server
+type Local struct {
+	Dir  string
+	Path string
+}
+
+func NewLocal(dir, urlPath string) (*Local, error) {
+	if dir == "" {
+		dir = "uploads"
+	}
+	if urlPath == "" {
+		urlPath = "/images"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Local{
+		Dir:  dir,
+		Path: "/" + strings.Trim(urlPath, "/"),
+	}, nil
+}
+
+//file is written to temporary file and renamed, so nobody reads half of it
+func (l *Local) Put(key string, data []byte, contentType string) error {
+	if !validKey(key) {
+		return ErrKey
+	}
+	name := l.file(key)
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+func (l *Local) Get(key string) ([]byte, error) {
+	if !validKey(key) {
+		return nil, ErrKey
+	}
+	data, err := os.ReadFile(l.file(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (l *Local) Delete(key string) error {
+	if !validKey(key) {
+		return ErrKey
+	}
+	err := os.Remove(l.file(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (l *Local) URL(key string) string {
+	return path.Join(l.Path, key)
+}
+
+func (l *Local) file(key string) string {
+	return filepath.Join(l.Dir, filepath.FromSlash(key))
+}
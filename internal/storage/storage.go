@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+//drivers of storage
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrDriver   = errors.New("error: unknown storage driver")
+	ErrKey      = errors.New("error: not valid storage key")
+	ErrNotFound = errors.New("error: object not found in storage")
+	ErrStorage  = errors.New("error: storage request failed")
+)
+
+//place for uploaded files, every backend is added as implementation of this interface
+type Storage interface {
+	Put(key string, data []byte, contentType string) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+	//public url of object
+	URL(key string) string
+}
+
+//storage settings from config
+type Config struct {
+	Driver string      `mapstructure:"driver"`
+	Local  LocalConfig `mapstructure:"local"`
+	S3     S3Config    `mapstructure:"s3"`
+}
+
+type LocalConfig struct {
+	//directory of files
+	Dir string `mapstructure:"dir"`
+	//path of server where files are available
+	Path string `mapstructure:"path"`
+}
+
+type S3Config struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	PathStyle bool   `mapstructure:"path_style"`
+	//url of bucket for clients, by default url of bucket in endpoint
+	PublicURL string `mapstructure:"public_url"`
+	AccessKey string `mapstructure:"-"`
+	SecretKey string `mapstructure:"-"`
+}
+
+//storage from "storage" section of config, keys of s3 are taken from env
+func Load() (Storage, error) {
+	var cfg Config
+	if err := viper.UnmarshalKey("storage", &cfg); err != nil {
+		return nil, err
+	}
+	cfg.S3.AccessKey = os.Getenv("S3_ACCESS_KEY")
+	cfg.S3.SecretKey = os.Getenv("S3_SECRET_KEY")
+	return New(cfg)
+}
+
+func New(cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case DriverLocal, "":
+		return NewLocal(cfg.Local.Dir, cfg.Local.Path)
+	case DriverS3:
+		return NewS3(cfg.S3)
+	default:
+		return nil, ErrDriver
+	}
+}
+
+//key is relative path without empty, "." and ".." parts
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}